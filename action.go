@@ -6,8 +6,14 @@
 package trello
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // Action represents Trello API actions
@@ -24,7 +30,10 @@ type Action struct {
 	Member          *Member     `json:"member,omitempty"`
 }
 
-// ActionData represent the nested data of actions
+// ActionData represent the nested data of actions. Besides the fields common
+// to the card actions (commentCard, createCard, copyCard, updateCard,
+// moveCardToBoard, ...) the undecoded data is kept in Raw, so the data of
+// action types without dedicated fields can still be decoded by the caller.
 type ActionData struct {
 	Text           string          `json:"text,omitempty"`
 	List           *List           `json:"list,omitempty"`
@@ -38,6 +47,26 @@ type ActionData struct {
 
 	CheckItem *CheckItem `json:"checkItem"`
 	Checklist *Checklist `json:"checklist"`
+
+	// BoardSource and BoardTarget are set by moveCardToBoard and
+	// moveCardFromBoard respectively.
+	BoardSource *Board `json:"boardSource,omitempty"`
+	BoardTarget *Board `json:"boardTarget,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the known fields of the action data and keeps a copy
+// of the whole object in Raw.
+func (d *ActionData) UnmarshalJSON(data []byte) error {
+	type actionData ActionData
+	var decoded actionData
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*d = ActionData(decoded)
+	d.Raw = append(json.RawMessage(nil), data...)
+	return nil
 }
 
 // ActionDataCard represent the nested 'card' data attribute of actions
@@ -61,6 +90,23 @@ func (b *Board) GetActions(extraArgs ...Arguments) (actions ActionCollection, er
 	return
 }
 
+// GetActionsSince takes a time, a list of action types and Arguments and
+// returns the actions of the receiver Board of these types which happened
+// after since. A zero since or an empty filter leaves the respective argument
+// out. Trello includes the memberCreator of every action unless
+// Arguments{"memberCreator": "false"} is given.
+func (b *Board) GetActionsSince(since time.Time, filter []string, extraArgs ...Arguments) (actions ActionCollection, err error) {
+	args := Arguments{}
+	if !since.IsZero() {
+		args["since"] = since.UTC().Format(time.RFC3339)
+	}
+	if len(filter) > 0 {
+		args["filter"] = strings.Join(filter, ",")
+	}
+	args.flatten(extraArgs)
+	return b.GetActions(args)
+}
+
 // GetActions makes a GET call for a list's actions
 func (l *List) GetActions(extraArgs ...Arguments) (actions ActionCollection, err error) {
 	args := flattenArguments(extraArgs)
@@ -116,6 +162,62 @@ func (c *Card) GetLastCommentAction() (*Action, error) {
 	return actions.LastCommentAction(), nil
 }
 
+// ResolvedComment is a comment on a card together with details about its author.
+type ResolvedComment struct {
+	ID              string
+	IDMemberCreator string
+	AuthorName      string
+	AuthorAvatarURL string
+	Text            string
+	Date            time.Time
+}
+
+// commentsPageSize is the number of comments requested at once, the most
+// Trello returns per request.
+const commentsPageSize = 1000
+
+// GetCommentsResolved takes Arguments and returns all the card's comments,
+// newest first, with the full name and avatar URL of each author resolved
+// from the memberCreator of the comment action. Trello returns at most 1000
+// comments per request, so they are requested page by page (of
+// Arguments["limit"] comments, if given) until the oldest one.
+func (c *Card) GetCommentsResolved(extraArgs ...Arguments) (comments []ResolvedComment, err error) {
+	args := Arguments{
+		"filter":               "commentCard",
+		"memberCreator_fields": "fullName,username,avatarUrl",
+		"limit":                strconv.Itoa(commentsPageSize),
+	}
+	args.flatten(extraArgs)
+	pageSize, _ := strconv.Atoi(args["limit"])
+
+	comments = []ResolvedComment{}
+	for {
+		actions, err := c.GetActions(args)
+		if err != nil {
+			return nil, err
+		}
+		for _, action := range actions {
+			comment := ResolvedComment{
+				ID:              action.ID,
+				IDMemberCreator: action.IDMemberCreator,
+				Date:            action.Date,
+			}
+			if action.Data != nil {
+				comment.Text = action.Data.Text
+			}
+			if action.MemberCreator != nil {
+				comment.AuthorName = action.MemberCreator.FullName
+				comment.AuthorAvatarURL = action.MemberCreator.AvatarURL
+			}
+			comments = append(comments, comment)
+		}
+		if len(actions) == 0 || len(actions) < pageSize {
+			return comments, nil
+		}
+		args["before"] = actions[len(actions)-1].ID
+	}
+}
+
 // DidCreateCard returns true if this action created a card, false otherwise.
 func (a *Action) DidCreateCard() bool {
 	switch a.Type {
@@ -183,6 +285,58 @@ func (a *Action) DidCommentCard() bool {
 	}
 }
 
+// IsMove returns true if this action moved a card, either to another list of
+// the same board or to another board.
+func (a *Action) IsMove() bool {
+	switch a.Type {
+	case "moveCardToBoard", "moveCardFromBoard":
+		return true
+	}
+	_, _, ok := a.MovedBetweenLists()
+	return ok
+}
+
+// MovedBetweenLists returns the IDs of the lists an updateCard action moved a
+// card from and to. ok is false if the action didn't move a card between
+// lists.
+func (a *Action) MovedBetweenLists() (from, to string, ok bool) {
+	if a.Type != "updateCard" || a.Data == nil || a.Data.ListBefore == nil || a.Data.ListAfter == nil {
+		return "", "", false
+	}
+	return a.Data.ListBefore.ID, a.Data.ListAfter.ID, true
+}
+
+// mentionPattern matches @username tokens which aren't part of an email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9_]+)`)
+
+// MentionedMembers extracts the @username mentions from the text of the
+// receiver comment action and resolves each of them to a Member. Every username
+// is only requested once per call; the @card and @board group mentions are
+// skipped. Arguments are passed along to each GetMember call.
+func (a *Action) MentionedMembers(extraArgs ...Arguments) (members []*Member, err error) {
+	if a.Data == nil {
+		return
+	}
+
+	resolved := map[string]*Member{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(a.Data.Text, -1) {
+		username := match[1]
+		if username == "card" || username == "board" {
+			continue
+		}
+		if _, ok := resolved[username]; ok {
+			continue
+		}
+		member, err := a.client.GetMember(username, extraArgs...)
+		if err != nil {
+			return members, errors.Wrapf(err, "Failed to resolve mention of '%s' in action %s", username, a.ID)
+		}
+		resolved[username] = member
+		members = append(members, member)
+	}
+	return
+}
+
 // SetClient can be used to override this Action's internal connection to
 // the Trello API. Normally, this is set automatically after API calls.
 func (a *Action) SetClient(newClient *Client) {
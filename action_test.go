@@ -6,7 +6,13 @@
 package trello
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestGetActionsOnBoard(t *testing.T) {
@@ -34,6 +40,90 @@ func TestGetActionsOnList(t *testing.T) {
 	}
 }
 
+func TestGetCommentsResolved(t *testing.T) {
+	card := testCard(t)
+	server := NewMockResponder(t, "actions", "card-comments-resolved.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Query().Get("filter") != "commentCard" {
+			t.Errorf("Expected filter=commentCard, got '%s'", r.URL.Query().Get("filter"))
+		}
+	})
+	defer server.Close()
+	card.client.BaseURL = server.URL()
+
+	comments, err := card.GetCommentsResolved(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("Expected 2 comments, got %d", len(comments))
+	}
+
+	if comments[0].AuthorName != "Ada Lovelace" {
+		t.Errorf("Expected author 'Ada Lovelace', got '%s'", comments[0].AuthorName)
+	}
+	if comments[0].AuthorAvatarURL != "https://trello-members.s3.amazonaws.com/5e8b1c2d3e4f5a6b7c8d9e0f/0a1b2c3d4e5f60718293a4b5c6d7e8f9" {
+		t.Errorf("Unexpected avatar URL '%s'", comments[0].AuthorAvatarURL)
+	}
+	if comments[0].Text != "Looks good to me, shipping it." {
+		t.Errorf("Unexpected comment text '%s'", comments[0].Text)
+	}
+	if comments[1].AuthorName != "Grace Hopper" {
+		t.Errorf("Expected author 'Grace Hopper', got '%s'", comments[1].AuthorName)
+	}
+	if comments[1].Date.Format(time.RFC3339) != "2020-07-26T10:00:00Z" {
+		t.Errorf("Unexpected comment date %v", comments[1].Date)
+	}
+}
+
+func TestGetCommentsResolvedPaged(t *testing.T) {
+	card := testCard(t)
+	start := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	total := commentsPageSize + 2
+	commentID := func(i int) string { return fmt.Sprintf("5fd20c0a7b6e2d3c4b5%05x", i) }
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		if query.Get("limit") != strconv.Itoa(commentsPageSize) {
+			t.Errorf("Expected pages of %d comments, got limit '%s'", commentsPageSize, query.Get("limit"))
+		}
+		// Newest first, older than before if given
+		var page []map[string]interface{}
+		for i := total - 1; i >= 0 && len(page) < commentsPageSize; i-- {
+			if before := query.Get("before"); before != "" && commentID(i) >= before {
+				continue
+			}
+			page = append(page, map[string]interface{}{
+				"id":   commentID(i),
+				"type": "commentCard",
+				"date": start.Add(time.Duration(i) * time.Minute),
+				"data": map[string]string{"text": fmt.Sprintf("Comment %d", i)},
+			})
+		}
+		json.NewEncoder(rw).Encode(page)
+	}))
+	defer server.Close()
+	card.client.BaseURL = server.URL
+
+	comments, err := card.GetCommentsResolved()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 pages to be requested, got %d", requests)
+	}
+	if len(comments) != total {
+		t.Fatalf("Expected all %d comments, got %d", total, len(comments))
+	}
+	for _, i := range []int{0, commentsPageSize, total - 1} {
+		if expected := fmt.Sprintf("Comment %d", total-1-i); comments[i].Text != expected {
+			t.Errorf("Expected %q in position %d, got %q", expected, i, comments[i].Text)
+		}
+	}
+}
+
 func TestGetActionsOnCard(t *testing.T) {
 	card := testCard(t)
 	card.client.BaseURL = mockResponse("actions", "card-actions-api-example.json").URL
@@ -151,3 +241,148 @@ func TestActionSetClient(t *testing.T) {
 		t.Error("Expected non-nil Action.client")
 	}
 }
+
+func TestActionMentionedMembers(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t)
+	defer server.Close()
+	requests := 0
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		requests++
+	})
+	c.BaseURL = server.URL()
+
+	action := &Action{
+		ID:   "5f3c0c0a7b6e2d3c4b5a0001",
+		Type: "commentCard",
+		Data: &ActionData{Text: "@bentleycook can you pair with @bobtester on this? cc @bentleycook, mail bob@example.com and @card"},
+	}
+	action.SetClient(c)
+
+	members, err := action.MentionedMembers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(members) != 2 {
+		t.Fatalf("Expected 2 mentioned members, got %d", len(members))
+	}
+	if members[0].Username != "bentleycook" || members[1].Username != "bobtester" {
+		t.Errorf("Expected bentleycook and bobtester, got %s and %s", members[0].Username, members[1].Username)
+	}
+	if members[1].FullName != "Bob Tester" {
+		t.Errorf("Expected full name 'Bob Tester', got '%s'", members[1].FullName)
+	}
+	if requests != 2 {
+		t.Errorf("Expected each username to be requested once, got %d requests", requests)
+	}
+}
+
+func TestActionCommentAccessors(t *testing.T) {
+	card := testCard(t)
+	card.client.BaseURL = mockResponse("actions", "card-actions-comment.json").URL
+	actions, err := card.GetActions(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comment := actions[0]
+	if !comment.DidCommentCard() || comment.IsMove() {
+		t.Errorf("Expected a comment which isn't a move")
+	}
+	if comment.Data.Text != "Ready for review @bobtester" {
+		t.Errorf("Expected the comment text, got '%s'", comment.Data.Text)
+	}
+	if comment.MemberCreator == nil || comment.MemberCreator.Username != "cfadl" {
+		t.Errorf("Expected the member creator to be decoded")
+	}
+	if _, _, ok := comment.MovedBetweenLists(); ok {
+		t.Errorf("Didn't expect a comment to move a card between lists")
+	}
+}
+
+func TestActionMoveAccessors(t *testing.T) {
+	card := testCard(t)
+	card.client.BaseURL = mockResponse("actions", "card-actions-move.json").URL
+	actions, err := card.GetActions(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from, to, ok := actions[0].MovedBetweenLists()
+	if !ok || !actions[0].IsMove() {
+		t.Fatal("Expected the updateCard action to move the card between lists")
+	}
+	if from != "57f03a10947d01faa6f5d8e5" || to != "57f03a11ab00f21e8197bef5" {
+		t.Errorf("Expected a move from QA to Done, got %s to %s", from, to)
+	}
+
+	toBoard := actions[1]
+	if !toBoard.IsMove() {
+		t.Error("Expected moveCardToBoard to be a move")
+	}
+	if _, _, ok := toBoard.MovedBetweenLists(); ok {
+		t.Error("Didn't expect moveCardToBoard to report a move between lists")
+	}
+	if toBoard.Data.BoardSource == nil || toBoard.Data.BoardSource.ID != "57f039fbc0f98772398d289d" {
+		t.Error("Expected the source board to be decoded")
+	}
+}
+
+func TestActionDataKeepsRaw(t *testing.T) {
+	var action Action
+	err := json.Unmarshal([]byte(`{"type": "addAttachmentToCard", "data": {"attachment": {"id": "5fd20c0a7b6e2d3c4b5a00a1", "name": "spec.pdf"}}}`), &action)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data struct {
+		Attachment struct {
+			Name string `json:"name"`
+		} `json:"attachment"`
+	}
+	err = json.Unmarshal(action.Data.Raw, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Attachment.Name != "spec.pdf" {
+		t.Errorf("Expected the raw data to hold the attachment, got '%s'", action.Data.Raw)
+	}
+	if action.IsMove() {
+		t.Error("Didn't expect an attachment to be a move")
+	}
+}
+
+func TestBoardGetActionsSince(t *testing.T) {
+	board := testBoard(t)
+	server := NewMockResponder(t, "actions", "board-actions-copyCard.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("since") != "2016-10-05T12:00:00Z" {
+			t.Errorf("Expected since '2016-10-05T12:00:00Z', got '%s'", query.Get("since"))
+		}
+		if query.Get("filter") != "copyCard,createCard" {
+			t.Errorf("Expected filter 'copyCard,createCard', got '%s'", query.Get("filter"))
+		}
+		if query.Get("memberCreator") != "true" {
+			t.Errorf("Expected memberCreator to be passed along, got '%s'", query.Get("memberCreator"))
+		}
+	})
+	board.client.BaseURL = server.URL()
+
+	since := time.Date(2016, 10, 5, 9, 0, 0, 0, time.FixedZone("ART", -3*60*60))
+	actions, err := board.GetActionsSince(since, []string{"copyCard", "createCard"}, Arguments{"memberCreator": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].Type != "copyCard" {
+		t.Fatalf("Expected a single copyCard action, got %d", len(actions))
+	}
+	if actions[0].MemberCreator == nil {
+		t.Error("Expected the member creator to be populated")
+	}
+	if actions[0].client == nil {
+		t.Error("Expected the action to have a client")
+	}
+}
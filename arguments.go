@@ -7,6 +7,7 @@ package trello
 
 import (
 	"net/url"
+	"strings"
 )
 
 // Arguments are used for passing URL parameters to the client for making API calls.
@@ -43,3 +44,23 @@ func (args Arguments) flatten(extraArgs []Arguments) {
 		}
 	}
 }
+
+// ensureField adds field to Arguments["fields"] unless it's missing (Trello
+// then returns its default fields), "all" or lists field already. Methods
+// relying on a field use it so that a caller restricting the fields can't
+// drop it.
+func (args Arguments) ensureField(field string) {
+	if fields, ok := args["fields"]; ok && fields != "all" && !containsField(fields, field) {
+		args["fields"] = fields + "," + field
+	}
+}
+
+// containsField returns true if the comma separated fields contain field.
+func containsField(fields, field string) bool {
+	for _, f := range strings.Split(fields, ",") {
+		if strings.TrimSpace(f) == field {
+			return true
+		}
+	}
+	return false
+}
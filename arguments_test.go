@@ -24,3 +24,22 @@ func TestSingleArgument(t *testing.T) {
 		t.Errorf("Expected 'limit=1000', but got '%s' instead.", queryString)
 	}
 }
+
+func TestEnsureField(t *testing.T) {
+	tests := []struct {
+		args     Arguments
+		expected string
+		present  bool
+	}{
+		{Arguments{}, "", false},
+		{Arguments{"fields": "all"}, "all", true},
+		{Arguments{"fields": "name"}, "name,idBoard", true},
+		{Arguments{"fields": "name, idBoard"}, "name, idBoard", true},
+	}
+	for _, test := range tests {
+		test.args.ensureField("idBoard")
+		if fields, ok := test.args["fields"]; fields != test.expected || ok != test.present {
+			t.Errorf("Expected fields '%s', got '%s'", test.expected, fields)
+		}
+	}
+}
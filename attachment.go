@@ -5,6 +5,15 @@
 
 package trello
 
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 // Attachment represent the attachments of cards. This is a nested resource of Card.
 // https://developers.trello.com/reference/#attachments
 type Attachment struct {
@@ -33,8 +42,163 @@ type AttachmentPreview struct {
 	Scaled bool   `json:"scaled"`
 }
 
+// BestPreview returns the widest preview of the attachment which is at most
+// maxWidth pixels wide. If every preview is wider, the narrowest one is
+// returned instead. It returns nil if the attachment has no previews.
+func (a *Attachment) BestPreview(maxWidth int) *AttachmentPreview {
+	if a == nil {
+		return nil
+	}
+	var best, smallest *AttachmentPreview
+	for i := range a.Previews {
+		preview := &a.Previews[i]
+		if smallest == nil || preview.Width < smallest.Width {
+			smallest = preview
+		}
+		if preview.Width <= maxWidth && (best == nil || preview.Width > best.Width) {
+			best = preview
+		}
+	}
+	if best == nil {
+		return smallest
+	}
+	return best
+}
+
 // SetClient can be used to override this Attachment's internal connection to
 // the Trello API. Normally, this is set automatically after API calls.
 func (a *Attachment) SetClient(newClient *Client) {
 	a.client = newClient
 }
+
+// AuthenticatedURL returns the attachment's URL with the key and token of the
+// attachment's Client appended as query parameters, so that files hosted on
+// Trello can be downloaded by a client which isn't otherwise authenticated
+// (e.g. a browser behind a proxy). URLs of attachments hosted outside of
+// Trello are returned unchanged.
+//
+// Be aware that the returned URL embeds the member's credentials. Anybody who
+// gets hold of it (browser history, proxy or server logs, referrer headers)
+// can act on Trello as that member until the token is revoked, so it should
+// never be handed to untrusted parties.
+func (a *Attachment) AuthenticatedURL() string {
+	u, err := url.Parse(a.URL)
+	if err != nil || a.client == nil {
+		return a.URL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "trello.com" && !strings.HasSuffix(host, ".trello.com") {
+		return a.URL
+	}
+
+	params := u.Query()
+	if a.client.Key != "" {
+		params.Set("key", a.client.Key)
+	}
+	if a.client.Token != "" {
+		params.Set("token", a.client.Token)
+	}
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+// AttachFileWithProgress uploads the file read from r as an attachment named
+// name to the receiver Card and returns the new Attachment. The file is read
+// while it's being sent, and onProgress is called with the number of bytes
+// uploaded so far and size, at most about every percent of size (or every
+// 64 KiB if size isn't known, i.e. not positive) and once at the end. An empty
+// mimeType is sent as application/octet-stream. The upload isn't retried if
+// Trello rejects it with a rate-limit error.
+func (c *Card) AttachFileWithProgress(name string, r io.Reader, size int64, mimeType string, onProgress func(written, total int64)) (*Attachment, error) {
+	path := fmt.Sprintf("cards/%s/attachments", c.ID)
+	if onProgress != nil {
+		r = &progressReader{r: r, total: size, step: progressStep(size), onProgress: onProgress}
+	}
+
+	attachment := &Attachment{}
+	err := c.client.postFileStreaming(path, Arguments{"name": name}, attachment, name, mimeType, r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error uploading attachment to card %s", c.ID)
+	}
+	attachment.SetClient(c.client)
+	attachment.Card = c
+	return attachment, nil
+}
+
+// GetAttachment takes an attachment id and Arguments and returns the
+// attachment of the receiver Card. Its Previews are always requested, even if
+// Arguments{"fields": "..."} doesn't list them.
+func (c *Card) GetAttachment(attachmentID string, extraArgs ...Arguments) (*Attachment, error) {
+	args := flattenArguments(extraArgs)
+	args.ensureField("previews")
+
+	path := fmt.Sprintf("cards/%s/attachments/%s", c.ID, attachmentID)
+	attachment := &Attachment{}
+	err := c.client.Get(path, args, attachment)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get attachment %s of card %s", attachmentID, c.ID)
+	}
+	attachment.SetClient(c.client)
+	attachment.Card = c
+	return attachment, nil
+}
+
+// SetCoverFromAttachment makes the attachment with the given id the cover of
+// the receiver Card and updates the card from the response. If the card's
+// Attachments are loaded, an attachment which isn't among them is rejected
+// without a request.
+func (c *Card) SetCoverFromAttachment(attachmentID string) error {
+	if len(c.Attachments) > 0 {
+		found := false
+		for _, attachment := range c.Attachments {
+			if attachment.ID == attachmentID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("Card %s has no attachment %s to use as cover", c.ID, attachmentID)
+		}
+	}
+
+	path := fmt.Sprintf("cards/%s", c.ID)
+	err := c.client.Put(path, Arguments{"idAttachmentCover": attachmentID}, c)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to set attachment %s as cover of card %s", attachmentID, c.ID)
+	}
+	return nil
+}
+
+// progressStep returns the number of bytes between two progress reports.
+func progressStep(total int64) int64 {
+	if total >= 100 {
+		return total / 100
+	}
+	if total > 0 {
+		return 1
+	}
+	return 64 * 1024
+}
+
+// progressReader reports the number of bytes read through it, whenever step
+// more bytes were read since the last report and when the end is reached.
+type progressReader struct {
+	r          io.Reader
+	total      int64
+	step       int64
+	read       int64
+	reported   int64
+	onProgress func(written, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	done := err == io.EOF || (p.total > 0 && p.read >= p.total)
+	if p.read-p.reported >= p.step || (done && p.read > p.reported) {
+		p.reported = p.read
+		p.onProgress(p.read, p.total)
+	}
+	return n, err
+}
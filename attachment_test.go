@@ -1,6 +1,14 @@
 package trello
 
-import "testing"
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
 
 func TestAttachmentSetClient(t *testing.T) {
 	a := Attachment{}
@@ -10,3 +18,198 @@ func TestAttachmentSetClient(t *testing.T) {
 		t.Error("Expected non-nil Attachment.client")
 	}
 }
+
+func TestAttachmentAuthenticatedURL(t *testing.T) {
+	a := Attachment{URL: "https://trello.com/1/cards/5bbce18fa4a337483b145a50/attachments/5bbce18fa4a337483b145a57/download/image.png"}
+	a.SetClient(testClient())
+
+	u, err := url.Parse(a.AuthenticatedURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("key") != "user" || u.Query().Get("token") != "pass" {
+		t.Errorf("Expected key and token to be appended, got '%s'", u.String())
+	}
+	if u.Path != "/1/cards/5bbce18fa4a337483b145a50/attachments/5bbce18fa4a337483b145a57/download/image.png" {
+		t.Errorf("Expected the path to be unchanged, got '%s'", u.Path)
+	}
+}
+
+func TestAttachmentAuthenticatedURLExternal(t *testing.T) {
+	a := Attachment{URL: "https://github.com/test"}
+	a.SetClient(testClient())
+
+	if a.AuthenticatedURL() != "https://github.com/test" {
+		t.Errorf("Expected external URL to be unchanged, got '%s'", a.AuthenticatedURL())
+	}
+}
+
+func TestAttachFileWithProgress(t *testing.T) {
+	const size = 1 << 20
+	var received int64
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("Expected a multipart file, got %v", err)
+			return
+		}
+		received, _ = io.Copy(ioutil.Discard, file)
+		if header.Header.Get("Content-Type") != "application/pdf" {
+			t.Errorf("Expected Content-Type application/pdf, got '%s'", header.Header.Get("Content-Type"))
+		}
+		rw.Write([]byte(`{"id": "5bbce18fa4a337483b145a57", "name": "manual.pdf", "isUpload": true, "mimeType": "application/pdf"}`))
+	}))
+	defer server.Close()
+
+	card := testCard(t)
+	card.client.BaseURL = server.URL
+
+	var reports [][2]int64
+	data := bytes.NewReader(make([]byte, size))
+	attachment, err := card.AttachFileWithProgress("manual.pdf", data, size, "application/pdf", func(written, total int64) {
+		reports = append(reports, [2]int64{written, total})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.ID != "5bbce18fa4a337483b145a57" || attachment.client == nil {
+		t.Errorf("Expected the uploaded attachment with a client, got %+v", attachment)
+	}
+	if received != size {
+		t.Errorf("Expected %d bytes to be uploaded, got %d", size, received)
+	}
+
+	if len(reports) < 2 || len(reports) > 101 {
+		t.Fatalf("Expected between 2 and 101 progress reports, got %d", len(reports))
+	}
+	for i, report := range reports {
+		if report[1] != size {
+			t.Errorf("Expected total %d, got %d", size, report[1])
+		}
+		if i > 0 && report[0] <= reports[i-1][0] {
+			t.Errorf("Expected increasing progress, got %d after %d", report[0], reports[i-1][0])
+		}
+	}
+	if last := reports[len(reports)-1][0]; last != size {
+		t.Errorf("Expected the last report at %d, got %d", size, last)
+	}
+}
+
+func TestCardGetAttachment(t *testing.T) {
+	card := testCard(t)
+	server := NewMockResponder(t, "cards", "4eea503d91e31d174600008f", "attachments", "5f40c0a7b6e2d3c4b5a0a001.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Path != "/cards/4eea503d91e31d174600008f/attachments/5f40c0a7b6e2d3c4b5a0a001" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if fields := r.URL.Query().Get("fields"); fields != "name,url,previews" {
+			t.Errorf("Expected the previews to be requested, got fields '%s'", fields)
+		}
+	})
+	card.client.BaseURL = server.URL()
+
+	attachment, err := card.GetAttachment("5f40c0a7b6e2d3c4b5a0a001", Arguments{"fields": "name,url"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.Name != "screenshot.png" || attachment.MimeType != "image/png" {
+		t.Errorf("Unexpected attachment %+v", attachment)
+	}
+	if len(attachment.Previews) != 2 || attachment.Previews[1].Width != 1280 {
+		t.Errorf("Expected 2 previews, got %+v", attachment.Previews)
+	}
+	if attachment.Card != card || attachment.client == nil {
+		t.Error("Expected the attachment to pick up the card and a client")
+	}
+}
+
+func TestCardSetCoverFromAttachment(t *testing.T) {
+	card := testCard(t)
+	server := NewMockResponder(t, "cards", "card-attachment-cover.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/cards/4eea503d91e31d174600008f" {
+			t.Errorf("Expected PUT on the card, got %s %s", r.Method, r.URL.Path)
+		}
+		if v := r.URL.Query().Get("idAttachmentCover"); v != "5f40c0a7b6e2d3c4b5a0a001" {
+			t.Errorf("Expected idAttachmentCover to be sent, got '%s'", v)
+		}
+	})
+	card.client.BaseURL = server.URL()
+
+	card.Attachments = []*Attachment{{ID: "5f40c0a7b6e2d3c4b5a0a001"}}
+	err := card.SetCoverFromAttachment("5f40c0a7b6e2d3c4b5a0a001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.IDAttachmentCover != "5f40c0a7b6e2d3c4b5a0a001" {
+		t.Errorf("Expected the cover attachment to be updated, got '%s'", card.IDAttachmentCover)
+	}
+	if card.Cover == nil || card.Cover.IDAttachment != "5f40c0a7b6e2d3c4b5a0a001" {
+		t.Errorf("Expected the cover to be updated, got %+v", card.Cover)
+	}
+}
+
+func TestCardSetCoverFromUnknownAttachment(t *testing.T) {
+	card := testCard(t)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		t.Errorf("Didn't expect a request, got %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+	card.client.BaseURL = server.URL
+
+	card.Attachments = []*Attachment{{ID: "5f40c0a7b6e2d3c4b5a0a001"}}
+	err := card.SetCoverFromAttachment("5f40c0a7b6e2d3c4b5a0a0ff")
+	if err == nil {
+		t.Error("Expected an error for an attachment the card doesn't have")
+	}
+}
+
+func TestAttachmentBestPreview(t *testing.T) {
+	card := testCard(t)
+	server := NewMockResponder(t, "cards", "4eea503d91e31d174600008f", "attachments", "5f40c0a7b6e2d3c4b5a0a010.json")
+	defer server.Close()
+	card.client.BaseURL = server.URL()
+
+	attachment, err := card.GetAttachment("5f40c0a7b6e2d3c4b5a0a010")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		maxWidth int
+		width    int
+	}{
+		{10000, 1920},
+		{1920, 1920},
+		{1000, 600},
+		{599, 250},
+		{250, 250},
+		{100, 70},
+		{50, 70},
+		{0, 70},
+	}
+	for _, test := range tests {
+		preview := attachment.BestPreview(test.maxWidth)
+		if preview == nil {
+			t.Fatalf("Expected a preview for a budget of %d", test.maxWidth)
+		}
+		if preview.Width != test.width {
+			t.Errorf("Expected the %dpx preview for a budget of %d, got %dpx", test.width, test.maxWidth, preview.Width)
+		}
+	}
+	if preview := attachment.BestPreview(1000); preview.ID != "5f40c0a7b6e2d3c4b5a0a011" || !preview.Scaled {
+		t.Errorf("Unexpected preview %+v", preview)
+	}
+}
+
+func TestAttachmentBestPreviewWithoutPreviews(t *testing.T) {
+	if preview := (&Attachment{}).BestPreview(1000); preview != nil {
+		t.Errorf("Expected no preview, got %+v", preview)
+	}
+	var attachment *Attachment
+	if preview := attachment.BestPreview(1000); preview != nil {
+		t.Errorf("Expected no preview for a nil attachment, got %+v", preview)
+	}
+}
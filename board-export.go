@@ -0,0 +1,112 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ExportJSON writes a JSON export of the receiver Board to w. The export is an
+// object holding the board's id, name and desc followed by its "lists",
+// "cards" and "checklists" arrays. Each collection is fetched and written in
+// turn, element by element, so the complete export is never held in memory.
+// Arguments are passed along to each of the collection requests.
+func (b *Board) ExportJSON(w io.Writer, extraArgs ...Arguments) error {
+	header, err := json.Marshal(struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Desc string `json:"desc"`
+	}{b.ID, b.Name, b.Desc})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to encode export header of board %s", b.ID)
+	}
+	// Drop the closing brace, the collections are appended below
+	if _, err = w.Write(header[:len(header)-1]); err != nil {
+		return errors.Wrapf(err, "Failed to write export of board %s", b.ID)
+	}
+
+	lists, err := b.GetLists(extraArgs...)
+	if err != nil {
+		return errors.Wrapf(err, "ExportJSON() failed to get the lists of board %s", b.ID)
+	}
+	if err = writeJSONArray(w, "lists", len(lists), func(i int) interface{} { return lists[i] }); err != nil {
+		return errors.Wrapf(err, "Failed to write the lists of board %s", b.ID)
+	}
+	lists = nil
+
+	cards, err := b.GetCards(extraArgs...)
+	if err != nil {
+		return errors.Wrapf(err, "ExportJSON() failed to get the cards of board %s", b.ID)
+	}
+	if err = writeJSONArray(w, "cards", len(cards), func(i int) interface{} { return cards[i] }); err != nil {
+		return errors.Wrapf(err, "Failed to write the cards of board %s", b.ID)
+	}
+	cards = nil
+
+	checklists, err := b.GetChecklists(extraArgs...)
+	if err != nil {
+		return errors.Wrapf(err, "ExportJSON() failed to get the checklists of board %s", b.ID)
+	}
+	if err = writeJSONArray(w, "checklists", len(checklists), func(i int) interface{} { return checklists[i] }); err != nil {
+		return errors.Wrapf(err, "Failed to write the checklists of board %s", b.ID)
+	}
+
+	if _, err = io.WriteString(w, "}"); err != nil {
+		return errors.Wrapf(err, "Failed to write export of board %s", b.ID)
+	}
+	return nil
+}
+
+// ExportCards writes every card of the receiver Board to w as JSON Lines, one
+// card per line, and returns the number of cards written. The cards are
+// fetched and written a page at a time, so memory use doesn't grow with the
+// size of the board. A failing write stops the export before the next page is
+// requested. Arguments are passed along to the cards requests.
+func (b *Board) ExportCards(w io.Writer, extraArgs ...Arguments) (int, error) {
+	enc := json.NewEncoder(w)
+	n := 0
+	err := b.eachCardPage(flattenArguments(extraArgs), func(cards []*Card) error {
+		for _, card := range cards {
+			if err := enc.Encode(card); err != nil {
+				return errors.Wrapf(err, "Failed to write card %s of board %s", card.ID, b.ID)
+			}
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		return n, errors.Wrapf(err, "ExportCards() failed on board %s", b.ID)
+	}
+	return n, nil
+}
+
+// writeJSONArray writes `,"key":[...]` to w, encoding the n elements returned
+// by item one at a time.
+func writeJSONArray(w io.Writer, key string, n int, item func(i int) interface{}) error {
+	if _, err := fmt.Fprintf(w, `,%q:[`, key); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		b, err := json.Marshal(item(i))
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(b); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
@@ -0,0 +1,138 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestBoardExportJSON(t *testing.T) {
+	c := testClient()
+	server := mockDynamicPathResponse()
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	board := &Board{ID: "5f2b0c0a7b6e2d3c4b5a0001", Name: "Releases"}
+	board.SetClient(c)
+
+	var buf bytes.Buffer
+	err := board.ExportJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("Expected valid JSON, got %s", buf.String())
+	}
+
+	var export struct {
+		ID         string       `json:"id"`
+		Name       string       `json:"name"`
+		Lists      []*List      `json:"lists"`
+		Cards      []*Card      `json:"cards"`
+		Checklists []*Checklist `json:"checklists"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &export)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if export.ID != board.ID || export.Name != "Releases" {
+		t.Errorf("Unexpected board header id '%s' name '%s'", export.ID, export.Name)
+	}
+	if len(export.Lists) != 2 {
+		t.Errorf("Expected 2 lists, got %d", len(export.Lists))
+	}
+	if len(export.Cards) != 2 {
+		t.Errorf("Expected 2 cards, got %d", len(export.Cards))
+	}
+	if len(export.Checklists) != 1 || len(export.Checklists[0].CheckItems) != 1 {
+		t.Fatalf("Expected 1 checklist with 1 item, got %v", export.Checklists)
+	}
+	if export.Checklists[0].CheckItems[0].State != "complete" {
+		t.Errorf("Expected check item state 'complete', got '%s'", export.Checklists[0].CheckItems[0].State)
+	}
+}
+
+func TestBoardExportCards(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t)
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	board := &Board{ID: "5fc0c0a7b6e2d3c4b5a000e0"}
+	board.SetClient(c)
+
+	var buf bytes.Buffer
+	n, err := board.ExportCards(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("Expected 4 cards to be exported, got %d", n)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		card := Card{}
+		if err := json.Unmarshal(line, &card); err != nil {
+			t.Fatalf("Expected a JSON card per line, got '%s': %v", line, err)
+		}
+		ids = append(ids, card.ID)
+	}
+	expected := []string{"5fc0c0a7b6e2d3c4b5a000e4", "5fc0c0a7b6e2d3c4b5a000e3", "5fc0c0a7b6e2d3c4b5a000e2", "5fc0c0a7b6e2d3c4b5a000e1"}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d", len(expected), len(ids))
+	}
+	for i, id := range expected {
+		if ids[i] != id {
+			t.Errorf("Expected card %s on line %d, got %s", id, i+1, ids[i])
+		}
+	}
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestBoardExportCardsWriteError(t *testing.T) {
+	c := testClient()
+	requests := 0
+	server := NewMockResponder(t)
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		requests++
+	})
+	c.BaseURL = server.URL()
+
+	board := &Board{ID: "5fc0c0a7b6e2d3c4b5a000e0"}
+	board.SetClient(c)
+
+	w := &failingWriter{}
+	n, err := board.ExportCards(w)
+	if err == nil {
+		t.Fatal("Expected the write error to be returned")
+	}
+	if n != 0 || w.writes != 1 {
+		t.Errorf("Expected the export to stop at the first write, got %d cards and %d writes", n, w.writes)
+	}
+	if requests != 1 {
+		t.Errorf("Expected no further page to be requested, got %d requests", requests)
+	}
+}
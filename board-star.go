@@ -0,0 +1,88 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// BoardStar represents a board starred by a member. Stars are ordered by Pos,
+// like cards in a list.
+// https://developers.trello.com/reference/#membersidboardstars
+type BoardStar struct {
+	client   *Client
+	memberID string
+	ID       string  `json:"id"`
+	IDBoard  string  `json:"idBoard"`
+	Pos      float64 `json:"pos"`
+}
+
+// GetBoardStars takes Arguments and returns the board stars of the receiver
+// Member, sorted by Trello by their Pos.
+func (m *Member) GetBoardStars(extraArgs ...Arguments) (stars []*BoardStar, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("members/%s/boardStars", m.ID)
+	err = m.client.Get(path, args, &stars)
+	for _, star := range stars {
+		star.SetClient(m.client)
+		star.memberID = m.ID
+	}
+	return
+}
+
+// Star stars the receiver Board for the member the token belongs to and
+// returns the new BoardStar. It's added after the existing stars, unless
+// Arguments{"pos": "top"} or a numeric pos is given.
+func (b *Board) Star(extraArgs ...Arguments) (*BoardStar, error) {
+	args := Arguments{
+		"idBoard": b.ID,
+		"pos":     "bottom",
+	}
+	args.flatten(extraArgs)
+
+	star := &BoardStar{}
+	err := b.client.Post("members/me/boardStars", args, star)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to star board %s", b.ID)
+	}
+	star.SetClient(b.client)
+	star.memberID = "me"
+	return star, nil
+}
+
+// Unstar takes the id of the receiver Board's star, as returned by Star or
+// GetBoardStars, and removes the star of the member the token belongs to.
+func (b *Board) Unstar(starID string) error {
+	path := fmt.Sprintf("members/me/boardStars/%s", starID)
+	err := b.client.Delete(path, Defaults(), nil)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to unstar board %s", b.ID)
+	}
+	return nil
+}
+
+// SetPos moves the receiver BoardStar among the member's stars. pos is
+// "top", "bottom" or a positive number. Pos is updated from the response.
+func (s *BoardStar) SetPos(pos string) error {
+	memberID := s.memberID
+	if memberID == "" {
+		memberID = "me"
+	}
+	path := fmt.Sprintf("members/%s/boardStars/%s", memberID, s.ID)
+	err := s.client.Put(path, Arguments{"pos": pos}, s)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to move board star %s", s.ID)
+	}
+	return nil
+}
+
+// SetClient can be used to override this BoardStar's internal connection to
+// the Trello API. Normally, this is set automatically after API calls.
+func (s *BoardStar) SetClient(newClient *Client) {
+	s.client = newClient
+}
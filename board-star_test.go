@@ -0,0 +1,96 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMemberGetBoardStars(t *testing.T) {
+	member := &Member{ID: "me"}
+	member.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	member.client.BaseURL = server.URL()
+
+	stars, err := member.GetBoardStars()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stars) != 2 {
+		t.Fatalf("Expected 2 board stars, got %d", len(stars))
+	}
+	if stars[1].IDBoard != "60400c0a7b6e2d3c4b5a00b0" || stars[1].Pos != 32768 {
+		t.Errorf("Unexpected second star %+v", stars[1])
+	}
+	if stars[0].client == nil {
+		t.Error("Expected the stars to have a client")
+	}
+}
+
+func TestBoardStar(t *testing.T) {
+	board := &Board{ID: "5f2b0c0a7b6e2d3c4b5a0001"}
+	board.SetClient(testClient())
+	server := NewMockResponder(t, "boardStars", "board-star-created.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/members/me/boardStars" {
+			t.Errorf("Expected POST on the member's board stars, got %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("idBoard") != board.ID || query.Get("pos") != "bottom" {
+			t.Errorf("Expected the board to be starred at the bottom, got '%s'", r.URL.RawQuery)
+		}
+	})
+	board.client.BaseURL = server.URL()
+
+	star, err := board.Star()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if star.ID != "5f50c0a7b6e2d3c4b5a0d003" || star.IDBoard != board.ID || star.Pos != 49152 {
+		t.Errorf("Unexpected star %+v", star)
+	}
+
+	server = NewMockResponder(t, "boardStars", "board-star-moved.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/members/me/boardStars/5f50c0a7b6e2d3c4b5a0d003" {
+			t.Errorf("Expected PUT on the star, got %s %s", r.Method, r.URL.Path)
+		}
+		if pos := r.URL.Query().Get("pos"); pos != "top" {
+			t.Errorf("Expected pos 'top', got '%s'", pos)
+		}
+	})
+	board.client.BaseURL = server.URL()
+
+	err = star.SetPos("top")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if star.Pos != 8192 {
+		t.Errorf("Expected the star to move to 8192, got %f", star.Pos)
+	}
+}
+
+func TestBoardUnstar(t *testing.T) {
+	board := &Board{ID: "4ed7e27fe6abb2517a21383d"}
+	board.SetClient(testClient())
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/members/me/boardStars/5f50c0a7b6e2d3c4b5a0d001" {
+			t.Errorf("Expected DELETE on the star, got %s %s", r.Method, r.URL.Path)
+		}
+		rw.Write([]byte(`{"limits": {}}`))
+	}))
+	defer server.Close()
+	board.client.BaseURL = server.URL
+
+	if err := board.Unstar("5f50c0a7b6e2d3c4b5a0d001"); err != nil {
+		t.Fatal(err)
+	}
+}
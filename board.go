@@ -8,6 +8,8 @@ package trello
 import (
 	"fmt"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 type BoardPrefs struct {
@@ -63,6 +65,25 @@ type Board struct {
 	Lists          []*List         `json:"lists"`
 	Actions        []*Action       `json:"actions"`
 	Organization   Organization    `json:"organization"`
+	PowerUps       []string        `json:"powerUps"`
+	Limits         BoardLimits     `json:"limits"`
+
+	listsCache *ttlCache
+}
+
+// BoardLimits holds the limits Trello applies to the objects on a board.
+type BoardLimits struct {
+	CustomFields struct {
+		PerBoard Limit `json:"perBoard"`
+	} `json:"customFields"`
+}
+
+// Limit describes one of Trello's object limits. Status is one of "ok",
+// "warn" or "disabled", the latter once the DisableAt count is reached.
+type Limit struct {
+	Status    string `json:"status"`
+	DisableAt int    `json:"disableAt"`
+	WarnAt    int    `json:"warnAt"`
 }
 
 // NewBoard is a constructor that sets the default values
@@ -113,6 +134,17 @@ func (b *Board) CreatedAt() time.Time {
 	return t
 }
 
+// HasPowerUp returns true if the named classic Power-Up (e.g. "calendar" or
+// "customFields") is listed in the board's legacy powerUps attribute.
+func (b *Board) HasPowerUp(name string) bool {
+	for _, powerUp := range b.PowerUps {
+		if powerUp == name {
+			return true
+		}
+	}
+	return false
+}
+
 // CreateBoard creates a board remote.
 // Attribute currently supported as extra argument: defaultLists, powerUps.
 // Attributes currently known to be unsupported: idBoardSource, keepFromSource.
@@ -163,6 +195,19 @@ func (b *Board) Update(extraArgs ...Arguments) error {
 	return b.client.PutBoard(b, args)
 }
 
+// SetPref PUTs a single board preference, e.g. "permissionLevel",
+// "cardCovers" or "selfJoin", and updates the struct from the returned board.
+// The server's error is returned as is, so an unknown pref or value can be
+// detected with IsBadRequest().
+func (b *Board) SetPref(pref, value string) error {
+	path := fmt.Sprintf("boards/%s/prefs/%s", b.ID, pref)
+	err := b.client.Put(path, Arguments{"value": value}, b)
+	if err == nil {
+		b.SetClient(b.client)
+	}
+	return err
+}
+
 // Delete makes a DELETE call for the receiver Board.
 func (b *Board) Delete(extraArgs ...Arguments) error {
 	args := flattenArguments(extraArgs)
@@ -202,9 +247,37 @@ func (c *Client) GetBoard(boardID string, extraArgs ...Arguments) (board *Board,
 	return
 }
 
+// GetBoard takes Arguments and returns the board the receiver Card is on.
+// The board is resolved by Trello, so IDBoard doesn't need to be loaded.
+func (c *Card) GetBoard(extraArgs ...Arguments) (board *Board, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("cards/%s/board", c.ID)
+	err = c.client.Get(path, args, &board)
+	if board != nil {
+		board.SetClient(c.client)
+	}
+	return
+}
+
+// GetBoard takes Arguments and returns the board of the receiver List. Only
+// the list's ID is needed, IDBoard may be empty.
+func (l *List) GetBoard(extraArgs ...Arguments) (board *Board, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("lists/%s/board", l.ID)
+	err = l.client.Get(path, args, &board)
+	if board != nil {
+		board.SetClient(l.client)
+	}
+	return
+}
+
 // GetMyBoards returns a slice of all boards associated with the credentials set on the client.
+// Only open boards are returned unless a filter such as Arguments{"filter": "closed"}
+// or Arguments{"filter": "all"} is given. Arguments{"fields": ...} limits the
+// board attributes loaded.
 func (c *Client) GetMyBoards(extraArgs ...Arguments) (boards []*Board, err error) {
-	args := flattenArguments(extraArgs)
+	args := Arguments{"filter": "open"}
+	args.flatten(extraArgs)
 	path := "members/me/boards"
 	err = c.Get(path, args, &boards)
 	for i := range boards {
@@ -224,6 +297,49 @@ func (m *Member) GetBoards(extraArgs ...Arguments) (boards []*Board, err error)
 	return
 }
 
+// GetSubscribedBoards takes Arguments and returns the boards of the receiver
+// Member the member is subscribed to (watching). Trello can't filter boards by
+// subscription, so all boards are requested and filtered here. The subscribed
+// field is always requested, even if Arguments{"fields": "..."} doesn't list it.
+func (m *Member) GetSubscribedBoards(extraArgs ...Arguments) ([]*Board, error) {
+	args := flattenArguments(extraArgs)
+	args.ensureField("subscribed")
+	boards, err := m.GetBoards(args)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribed := make([]*Board, 0, len(boards))
+	for _, board := range boards {
+		if board.Subscribed {
+			subscribed = append(subscribed, board)
+		}
+	}
+	return subscribed, nil
+}
+
+// Subscribe subscribes the member the token belongs to to the receiver Board,
+// i.e. starts watching it, and sets Subscribed.
+func (b *Board) Subscribe() error {
+	return b.setSubscribed(true)
+}
+
+// Unsubscribe stops the member the token belongs to from watching the
+// receiver Board and clears Subscribed.
+func (b *Board) Unsubscribe() error {
+	return b.setSubscribed(false)
+}
+
+func (b *Board) setSubscribed(value bool) error {
+	path := fmt.Sprintf("boards/%s/subscribed", b.ID)
+	err := b.client.Put(path, Arguments{"value": fmt.Sprintf("%t", value)}, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update the subscription to board %s", b.ID)
+	}
+	b.Subscribed = value
+	return nil
+}
+
 // PutBoard PUTs a board remote. Extra arguments are currently unsupported.
 //
 // API Docs: https://developers.trello.com/reference#idnext
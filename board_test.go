@@ -6,6 +6,8 @@
 package trello
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -142,6 +144,68 @@ func TestGetMyBoards(t *testing.T) {
 	}
 }
 
+func TestGetMyBoardsFilter(t *testing.T) {
+	tests := []struct {
+		args   []Arguments
+		filter string
+	}{
+		{nil, "open"},
+		{[]Arguments{{"filter": "all", "fields": "name,closed,url"}}, "all"},
+	}
+
+	for _, test := range tests {
+		c := testClient()
+		server := NewMockResponder(t, "boards", "my-boards-fields.json")
+		server.AssertRequest(func(t *testing.T, r *http.Request) {
+			if r.URL.Path != "/members/me/boards" {
+				t.Errorf("Unexpected path %s", r.URL.Path)
+			}
+			if filter := r.URL.Query().Get("filter"); filter != test.filter {
+				t.Errorf("Expected filter '%s', got '%s'", test.filter, filter)
+			}
+			if len(test.args) > 0 && r.URL.Query().Get("fields") != "name,closed,url" {
+				t.Errorf("Expected fields to be forwarded, got '%s'", r.URL.Query().Get("fields"))
+			}
+		})
+		c.BaseURL = server.URL()
+
+		boards, err := c.GetMyBoards(test.args...)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(boards) != 3 {
+			t.Errorf("Expected 3 boards, got %d", len(boards))
+		}
+		for _, board := range boards {
+			if board.client != c {
+				t.Errorf("Expected board %s to have the client set", board.ID)
+			}
+		}
+	}
+}
+
+func TestBoardHasPowerUp(t *testing.T) {
+	c := testClient()
+	c.BaseURL = mockResponse("boards", "powerUps.json").URL
+	board, err := c.GetBoard("pUw3rUps", Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(board.PowerUps) != 2 {
+		t.Errorf("Expected 2 power-ups, got %d", len(board.PowerUps))
+	}
+
+	if !board.HasPowerUp("calendar") {
+		t.Error("Expected board to have the calendar power-up")
+	}
+
+	if board.HasPowerUp("customFields") {
+		t.Error("Expected board not to have the customFields power-up")
+	}
+}
+
 func TestGetUnauthorizedBoard(t *testing.T) {
 	c := testClient()
 	c.BaseURL = mockErrorResponse(401).URL
@@ -301,3 +365,262 @@ func TestBoardSetClient(t *testing.T) {
 		t.Error("Expected non-nil board.client")
 	}
 }
+
+func TestBoardRename(t *testing.T) {
+	board := Board{ID: "5d2ccd3015468d3df508f10d", Name: "test-board-for-update plus"}
+	client := testClient()
+	board.SetClient(client)
+
+	server := NewMockResponder(t, "boards", "5d2ccd3015468d3df508f10d", "update.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/boards/5d2ccd3015468d3df508f10d" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if name := r.URL.Query().Get("name"); name != "test-board-for-update plus" {
+			t.Errorf("Expected name 'test-board-for-update plus', got '%s'", name)
+		}
+	})
+	client.BaseURL = server.URL()
+
+	err := board.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.Desc != "Some other description" {
+		t.Errorf("Expected the description to be re-hydrated, got '%s'", board.Desc)
+	}
+	if board.Prefs.Background != "blue" {
+		t.Errorf("Expected the prefs to be re-hydrated, got background '%s'", board.Prefs.Background)
+	}
+}
+
+func TestBoardSetPref(t *testing.T) {
+	board := Board{ID: "5d2ccd3015468d3df508f10d"}
+	client := testClient()
+	board.SetClient(client)
+
+	server := NewMockResponder(t, "boards", "5d2ccd3015468d3df508f10d", "permission-level-org.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/boards/5d2ccd3015468d3df508f10d/prefs/permissionLevel" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if value := r.URL.Query().Get("value"); value != "org" {
+			t.Errorf("Expected value 'org', got '%s'", value)
+		}
+	})
+	client.BaseURL = server.URL()
+
+	err := board.SetPref("permissionLevel", "org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.Prefs.PermissionLevel != "org" {
+		t.Errorf("Expected permission level 'org', got '%s'", board.Prefs.PermissionLevel)
+	}
+	if board.Name != "test-board-for-update plus" {
+		t.Errorf("Expected the board to be re-hydrated, got name '%s'", board.Name)
+	}
+}
+
+func TestBoardSetUnknownPref(t *testing.T) {
+	board := Board{ID: "5d2ccd3015468d3df508f10d"}
+	client := testClient()
+	board.SetClient(client)
+	client.BaseURL = mockErrorResponse(http.StatusBadRequest).URL
+
+	err := board.SetPref("notAPref", "true")
+	if err == nil {
+		t.Fatal("Expected an error for an unknown pref")
+	}
+	if !IsBadRequest(err) {
+		t.Errorf("Expected a bad-request error, got %v", err)
+	}
+}
+
+func TestCardGetBoard(t *testing.T) {
+	card := testCard(t)
+	card.IDBoard = ""
+
+	server := NewMockResponder(t)
+	defer server.Close()
+	card.client.BaseURL = server.URL()
+
+	board, err := card.GetBoard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.ID != "4eea4ffc91e31d1746000046" || board.Name != "Example Board" {
+		t.Errorf("Unexpected board %s '%s'", board.ID, board.Name)
+	}
+	if board.client != card.client {
+		t.Error("Expected the board to carry the card's client")
+	}
+}
+
+func TestListGetBoard(t *testing.T) {
+	list := &List{ID: "5ccd793e91682684235c0b13"}
+	list.SetClient(testClient())
+
+	server := NewMockResponder(t)
+	defer server.Close()
+	list.client.BaseURL = server.URL()
+
+	board, err := list.GetBoard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.ID != "5ccd793e91682684235c0b10" || board.Name != "Sprint Board" {
+		t.Errorf("Unexpected board %s '%s'", board.ID, board.Name)
+	}
+	if board.client != list.client {
+		t.Error("Expected the board to carry the list's client")
+	}
+}
+
+func TestBoardSubscribe(t *testing.T) {
+	board := &Board{ID: "4ed7e27fe6abb2517a21383d"}
+	board.SetClient(testClient())
+	var values []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/boards/4ed7e27fe6abb2517a21383d/subscribed" {
+			t.Errorf("Expected PUT on the board subscription, got %s %s", r.Method, r.URL.Path)
+		}
+		values = append(values, r.URL.Query().Get("value"))
+		rw.Write([]byte(`{"id": "4ed7e27fe6abb2517a21383d"}`))
+	}))
+	defer server.Close()
+	board.client.BaseURL = server.URL
+
+	if err := board.Subscribe(); err != nil {
+		t.Fatal(err)
+	}
+	if !board.Subscribed {
+		t.Error("Expected the board to be subscribed")
+	}
+	if err := board.Unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	if board.Subscribed {
+		t.Error("Expected the board to be unsubscribed")
+	}
+	if len(values) != 2 || values[0] != "true" || values[1] != "false" {
+		t.Errorf("Expected the values true and false, got %v", values)
+	}
+}
+
+func TestBoardSubscribeError(t *testing.T) {
+	board := &Board{ID: "4ed7e27fe6abb2517a21383d"}
+	board.SetClient(testClient())
+	server := mockErrorResponse(http.StatusUnauthorized)
+	defer server.Close()
+	board.client.BaseURL = server.URL
+
+	if err := board.Subscribe(); err == nil {
+		t.Error("Expected an error")
+	}
+	if board.Subscribed {
+		t.Error("Didn't expect the board to be subscribed after an error")
+	}
+}
+
+func TestMemberGetSubscribedBoards(t *testing.T) {
+	member := &Member{ID: "me"}
+	member.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	member.client.BaseURL = server.URL()
+
+	boards, err := member.GetSubscribedBoards(Arguments{"fields": "name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(boards) != 2 {
+		t.Fatalf("Expected 2 subscribed boards, got %d", len(boards))
+	}
+	if boards[0].Name != "Trello Development" || boards[1].Name != "Release Planning" {
+		t.Errorf("Unexpected boards '%s' and '%s'", boards[0].Name, boards[1].Name)
+	}
+	if boards[0].client == nil {
+		t.Error("Expected the boards to have a client")
+	}
+}
+
+func TestGetBoardMemberships(t *testing.T) {
+	board := testBoard(t)
+
+	server := NewMockResponder(t)
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Path != "/boards/4ed7e27fe6abb2517a21383d/memberships" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if filter := r.URL.Query().Get("filter"); filter != "all" {
+			t.Errorf("Expected filter=all, got '%s'", filter)
+		}
+	})
+	board.client.BaseURL = server.URL()
+
+	memberships, err := board.GetMemberships(Arguments{"filter": "all", "member": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(memberships) != 3 {
+		t.Fatalf("Expected 3 memberships, got %d", len(memberships))
+	}
+	if memberships[0].Type != "admin" || memberships[0].Deactivated || memberships[0].Unconfirmed {
+		t.Errorf("Expected an active admin, got %+v", memberships[0])
+	}
+	if memberships[1].Type != "normal" || !memberships[1].Deactivated {
+		t.Errorf("Expected a deactivated normal member, got %+v", memberships[1])
+	}
+	if !memberships[2].Unconfirmed || memberships[2].Deactivated {
+		t.Errorf("Expected an unconfirmed member, got %+v", memberships[2])
+	}
+	member := memberships[1].Member
+	if member == nil || member.ID != memberships[1].MemberID || member.Username != "formercontractor" {
+		t.Fatalf("Expected the member details to be inlined, got %+v", member)
+	}
+	if member.client == nil {
+		t.Error("Expected the inlined member to have a client")
+	}
+}
+
+func TestBoardCardsByMember(t *testing.T) {
+	board := testBoard(t)
+	server := NewMockResponder(t)
+	defer server.Close()
+	requests := 0
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		requests++
+	})
+	board.client.BaseURL = server.URL()
+
+	byMember, err := board.CardsByMember()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{
+		"4ee7df1be582acdec80000ae": 2,
+		"4ee7deffe582acdec80000ac": 1,
+		"":                         2,
+	}
+	if len(byMember) != len(expected) {
+		t.Errorf("Expected %d groups, got %d", len(expected), len(byMember))
+	}
+	for memberID, count := range expected {
+		if len(byMember[memberID]) != count {
+			t.Errorf("Expected %d cards for member '%s', got %d", count, memberID, len(byMember[memberID]))
+		}
+	}
+	if byMember[""][0].Name != "Triage inbox" {
+		t.Errorf("Expected 'Triage inbox' to be unassigned, got '%s'", byMember[""][0].Name)
+	}
+	// The cards plus the request confirming there are no older cards
+	if requests != 2 {
+		t.Errorf("Expected the cards to be fetched once, got %d requests", requests)
+	}
+}
@@ -6,10 +6,16 @@
 package trello
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,6 +32,19 @@ type CardBadges struct {
 	Attachments        int        `json:"attachments"`
 	Description        bool       `json:"description"`
 	Due                *time.Time `json:"due,omitempty"`
+	DueComplete        bool       `json:"dueComplete"`
+}
+
+// Cover represents the cover of a card. Cards without a cover have a zero
+// Cover, since Trello sends an empty object for them.
+type Cover struct {
+	Color                string `json:"color"`
+	IDAttachment         string `json:"idAttachment"`
+	IDUploadedBackground string `json:"idUploadedBackground"`
+	Size                 string `json:"size"`
+	Brightness           string `json:"brightness"`
+	EdgeColor            string `json:"edgeColor"`
+	SharedSourceURL      string `json:"sharedSourceUrl"`
 }
 
 // Card represents the card resource.
@@ -46,7 +65,11 @@ type Card struct {
 	Start            *time.Time `json:"start"`
 	Due              *time.Time `json:"due"`
 	DueComplete      bool       `json:"dueComplete"`
+	DueReminder      *int       `json:"dueReminder,omitempty"`
 	Closed           bool       `json:"closed"`
+	IsTemplate       bool       `json:"isTemplate"`
+	CardRole         string     `json:"cardRole,omitempty"`
+	IDMirrorSource   string     `json:"mirrorSourceId,omitempty"`
 	Subscribed       bool       `json:"subscribed"`
 	DateLastActivity *time.Time `json:"dateLastActivity"`
 
@@ -61,6 +84,9 @@ type Card struct {
 	// Badges
 	Badges CardBadges `json:"badges"`
 
+	// Cover
+	Cover *Cover `json:"cover,omitempty"`
+
 	// Actions
 	Actions ActionCollection `json:"actions,omitempty"`
 
@@ -87,6 +113,41 @@ type Card struct {
 	CustomFieldItems []*CustomFieldItem `json:"customFieldItems,omitempty"`
 
 	customFieldMap *map[string]interface{}
+
+	// hasBadges records whether the JSON the card was decoded from had
+	// badges, which tells all zero badges apart from missing ones.
+	hasBadges bool
+}
+
+// UnmarshalJSON decodes the card and records whether its badges were part of
+// the JSON.
+func (c *Card) UnmarshalJSON(data []byte) error {
+	type card Card
+	decoded := struct {
+		*card
+		Badges *CardBadges `json:"badges"`
+	}{card: (*card)(c)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	c.hasBadges = decoded.Badges != nil
+	if decoded.Badges != nil {
+		c.Badges = *decoded.Badges
+	}
+	return nil
+}
+
+// MarshalJSON encodes the card, leaving out the badges of a card which was
+// decoded without them, so they don't appear on decoding it again.
+func (c Card) MarshalJSON() ([]byte, error) {
+	type card Card
+	if c.hasBadges || c.Badges != (CardBadges{}) {
+		return json.Marshal(card(c))
+	}
+	return json.Marshal(struct {
+		card
+		Badges *CardBadges `json:"badges,omitempty"`
+	}{card: card(c)})
 }
 
 // SetClient can be used to override this Card's internal connection to the
@@ -133,6 +194,125 @@ func (c *Card) CreatedAt() time.Time {
 	return t
 }
 
+// DueFromBadge returns the due date and completion state reported by the
+// card's badges. Badges are part of most card responses, so this also works on
+// cards fetched without their due and dueComplete fields. The returned time is
+// nil if the card has no due date.
+func (c *Card) DueFromBadge() (*time.Time, bool) {
+	return c.Badges.Due, c.Badges.DueComplete
+}
+
+// dueSoonWithin is how close the due date of a card has to be for DueStatus
+// to report it as "due-soon", like Trello highlights it.
+const dueSoonWithin = 24 * time.Hour
+
+// IsOverdue returns true if the card's due date has passed and the card isn't
+// marked as complete. Cards without a due date are never overdue.
+func (c *Card) IsOverdue() bool {
+	return c.dueStatus(time.Now(), 0) == "overdue"
+}
+
+// IsDueSoon returns true if the card is due within the given duration from
+// now and isn't marked as complete. Overdue cards aren't due soon.
+func (c *Card) IsDueSoon(within time.Duration) bool {
+	return c.dueStatus(time.Now(), within) == "due-soon"
+}
+
+// DueStatus returns "none" for cards without a due date, "complete" for cards
+// marked as complete, "overdue" for cards past their due date, "due-soon" for
+// cards due within the next 24 hours and "upcoming" for the others. It only
+// looks at Due and DueComplete, there's no request.
+func (c *Card) DueStatus() string {
+	return c.dueStatus(time.Now(), dueSoonWithin)
+}
+
+func (c *Card) dueStatus(now time.Time, soon time.Duration) string {
+	switch {
+	case c.Due == nil:
+		return "none"
+	case c.DueComplete:
+		return "complete"
+	case c.Due.Before(now):
+		return "overdue"
+	case c.Due.Sub(now) <= soon:
+		return "due-soon"
+	default:
+		return "upcoming"
+	}
+}
+
+// ChecklistProgress returns the number of checked and the total number of
+// check items on the card's checklists, as reported by the card's badges.
+func (c *Card) ChecklistProgress() (checked, total int) {
+	return c.Badges.CheckItemsChecked, c.Badges.CheckItems
+}
+
+// GetCover fetches the cover of the card, stores it in Cover and returns it.
+func (c *Card) GetCover(extraArgs ...Arguments) (*Cover, error) {
+	args := Arguments{"fields": "cover"}
+	args.flatten(extraArgs)
+	path := fmt.Sprintf("cards/%s", c.ID)
+	var card Card
+	err := c.client.Get(path, args, &card)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the cover of card %s", c.ID)
+	}
+	if card.Cover == nil {
+		card.Cover = &Cover{}
+	}
+	c.Cover = card.Cover
+	return c.Cover, nil
+}
+
+// VoteCount returns the number of votes on the card. It's read from the
+// card's badges if the card was decoded with them, which is the case for most
+// card responses, or if they aren't all zero (e.g. set by hand). Otherwise it
+// costs a request to GetVotes. Unlike the other badge helpers it returns an
+// error, since that request can fail and a count of 0 would hide it.
+func (c *Card) VoteCount() (int, error) {
+	if c.hasBadges || c.Badges != (CardBadges{}) {
+		return c.Badges.Votes, nil
+	}
+	voters, err := c.GetVotes()
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to count the votes on card %s", c.ID)
+	}
+	return len(voters), nil
+}
+
+// CompletedCheckItems returns the states of the completed check items of all
+// checklists on the card. The states are only part of the card when it's
+// fetched with Arguments{"checkItemStates": "true"}.
+func (c *Card) CompletedCheckItems() []CheckItemState {
+	completed := []CheckItemState{}
+	for _, state := range c.CheckItemStates {
+		if state != nil && state.State == "complete" {
+			completed = append(completed, *state)
+		}
+	}
+	return completed
+}
+
+// CheckItemProgress returns the number of completed and the total number of
+// check items on the card's checklists. The completed count is taken from
+// CheckItemStates and the total from the card's Checklists, if they're loaded,
+// or its badges otherwise. Without CheckItemStates both counts come from the
+// badges, like ChecklistProgress.
+func (c *Card) CheckItemProgress() (complete, total int) {
+	if len(c.CheckItemStates) == 0 {
+		return c.ChecklistProgress()
+	}
+
+	complete = len(c.CompletedCheckItems())
+	if len(c.Checklists) == 0 {
+		return complete, c.Badges.CheckItems
+	}
+	for _, checklist := range c.Checklists {
+		total += len(checklist.CheckItems)
+	}
+	return
+}
+
 // CustomFields returns the card's custom fields.
 func (c *Card) CustomFields(boardCustomFields []*CustomField) map[string]interface{} {
 
@@ -185,6 +365,39 @@ func (c *Card) CustomFields(boardCustomFields []*CustomField) map[string]interfa
 	return *cfm
 }
 
+// CustomFieldItem returns the card's item of the custom field with the given
+// id, and false if the card has no value for the field (or its
+// CustomFieldItems aren't loaded).
+func (c *Card) CustomFieldItem(fieldID string) (*CustomFieldItem, bool) {
+	for _, item := range c.CustomFieldItems {
+		if item.IDCustomField == fieldID {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+// CustomFieldValue returns the value the card has for the given custom field,
+// like CustomFields does for all fields at once: the text of the selected
+// option for list (dropdown) fields, the value itself otherwise. It returns
+// false if the card has no value for the field, or the selected option isn't
+// one of the field's Options.
+func (c *Card) CustomFieldValue(field *CustomField) (interface{}, bool) {
+	item, ok := c.CustomFieldItem(field.ID)
+	if !ok {
+		return nil, false
+	}
+	if value := item.Value.Get(); value != nil {
+		return value, true
+	}
+	for _, option := range field.Options {
+		if option.ID == item.IDValue {
+			return option.Value.Text, true
+		}
+	}
+	return nil, false
+}
+
 // MoveToList moves a card to a list given by listID.
 func (c *Card) MoveToList(listID string, extraArgs ...Arguments) error {
 	args := flattenArguments(extraArgs)
@@ -193,10 +406,44 @@ func (c *Card) MoveToList(listID string, extraArgs ...Arguments) error {
 	return c.client.Put(path, args, &c)
 }
 
-// SetPos sets a card's new position.
-func (c *Card) SetPos(newPos float64) error {
+// SetPos sets a card's new position and updates the receiver from the response.
+// The position can be a positive number (float64, float32, int or int64) or
+// one of the strings "top" and "bottom". Any other value is rejected before
+// a request is made.
+func (c *Card) SetPos(newPos interface{}) error {
+	pos, err := posArgument(newPos)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid position for card %s", c.ID)
+	}
 	path := fmt.Sprintf("cards/%s", c.ID)
-	return c.client.Put(path, Arguments{"pos": fmt.Sprintf("%f", newPos)}, c)
+	return c.client.Put(path, Arguments{"pos": pos}, c)
+}
+
+// posArgument converts a position given as number or as "top"/"bottom"
+// into its argument representation.
+func posArgument(pos interface{}) (string, error) {
+	var value float64
+	switch p := pos.(type) {
+	case string:
+		if p == "top" || p == "bottom" {
+			return p, nil
+		}
+		return "", errors.Errorf("unsupported position '%s'", p)
+	case float64:
+		value = p
+	case float32:
+		value = float64(p)
+	case int:
+		value = float64(p)
+	case int64:
+		value = float64(p)
+	default:
+		return "", errors.Errorf("unsupported position type %T", pos)
+	}
+	if !(value > 0) || math.IsInf(value, 0) {
+		return "", errors.Errorf("position must be a positive number, got %v", value)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64), nil
 }
 
 // RemoveMember receives the id of a member and removes the corresponding member from the card.
@@ -213,7 +460,8 @@ func (c *Card) AddMemberID(memberID string) (member []*Member, err error) {
 	return member, err
 }
 
-// RemoveIDLabel removes a label id from the card.
+// RemoveIDLabel removes a label id from the card. It leaves IDLabels and
+// Labels untouched, RemoveLabelID also drops the label from both.
 func (c *Card) RemoveIDLabel(labelID string, label *Label) error {
 	path := fmt.Sprintf("cards/%s/idLabels/%s", c.ID, labelID)
 	return c.client.Delete(path, Defaults(), label)
@@ -221,11 +469,81 @@ func (c *Card) RemoveIDLabel(labelID string, label *Label) error {
 }
 
 // AddIDLabel receives a label id and adds the corresponding label or returns an error.
+// Only IDLabels is updated, AddLabelID also adds the label to Labels.
 func (c *Card) AddIDLabel(labelID string) error {
 	path := fmt.Sprintf("cards/%s/idLabels", c.ID)
 	return c.client.Post(path, Arguments{"value": labelID}, &c.IDLabels)
 }
 
+// AddLabelID adds the label with the given id to the card. IDLabels is
+// updated from the response and the label is fetched and appended to Labels,
+// unless it's there already.
+func (c *Card) AddLabelID(labelID string) error {
+	err := c.AddIDLabel(labelID)
+	if err != nil {
+		return errors.Wrapf(err, "Error adding label %s to card %s", labelID, c.ID)
+	}
+	for _, label := range c.Labels {
+		if label.ID == labelID {
+			return nil
+		}
+	}
+
+	label, err := c.client.GetLabel(labelID)
+	if err != nil {
+		return errors.Wrapf(err, "Label %s was added to card %s, but couldn't be fetched", labelID, c.ID)
+	}
+	label.SetClient(c.client)
+	c.Labels = append(c.Labels, label)
+	return nil
+}
+
+// RemoveLabelID removes the label with the given id from the card and from
+// its IDLabels and Labels. Removing a label which isn't on the card returns
+// Trello's error, for which IsBadRequest() or IsNotFound() is true.
+func (c *Card) RemoveLabelID(labelID string) error {
+	path := fmt.Sprintf("cards/%s/idLabels/%s", c.ID, labelID)
+	err := c.client.Delete(path, Defaults(), nil)
+	if err != nil {
+		return err
+	}
+
+	idLabels := c.IDLabels[:0]
+	for _, id := range c.IDLabels {
+		if id != labelID {
+			idLabels = append(idLabels, id)
+		}
+	}
+	c.IDLabels = idLabels
+
+	labels := c.Labels[:0]
+	for _, label := range c.Labels {
+		if label.ID != labelID {
+			labels = append(labels, label)
+		}
+	}
+	c.Labels = labels
+	return nil
+}
+
+// ResolveLabels returns the labels of boardLabels the card's IDLabels refer
+// to, in the order of IDLabels. IDs without a matching label are skipped, a
+// card without labels resolves to an empty slice.
+func (c *Card) ResolveLabels(boardLabels []*Label) []*Label {
+	byID := make(map[string]*Label, len(boardLabels))
+	for _, label := range boardLabels {
+		byID[label.ID] = label
+	}
+
+	labels := make([]*Label, 0, len(c.IDLabels))
+	for _, id := range c.IDLabels {
+		if label, ok := byID[id]; ok {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
 // MoveToTopOfList moves the card to the top of it's list.
 func (c *Card) MoveToTopOfList() error {
 	path := fmt.Sprintf("cards/%s", c.ID)
@@ -260,6 +578,96 @@ func (c *Card) Update(extraArgs ...Arguments) error {
 	return c.client.Put(path, args, c)
 }
 
+// UpdateIfUnchanged UPDATEs the card's attributes like Update, but only if the
+// card's dateLastActivity on the server is not newer than lastActivity (usually
+// the DateLastActivity the card was loaded with). Otherwise nothing is written
+// and an error is returned for which IsConflict() is true.
+//
+// The check and the update are separate requests, so this narrows the window
+// for lost updates between concurrent writers rather than closing it.
+func (c *Card) UpdateIfUnchanged(lastActivity time.Time, extraArgs ...Arguments) error {
+	path := fmt.Sprintf("cards/%s", c.ID)
+	current := Card{}
+	err := c.client.Get(path, Arguments{"fields": "dateLastActivity"}, &current)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to check the last activity of card %s", c.ID)
+	}
+
+	if current.DateLastActivity != nil && current.DateLastActivity.After(lastActivity) {
+		return &staleError{
+			msg: fmt.Sprintf("Card %s was modified at %s, after the expected %s", c.ID, current.DateLastActivity.Format(time.RFC3339), lastActivity.Format(time.RFC3339)),
+		}
+	}
+
+	return c.Update(extraArgs...)
+}
+
+// SetDue sets the card's due date and updates the receiver from the response.
+func (c *Card) SetDue(due time.Time) error {
+	path := fmt.Sprintf("cards/%s", c.ID)
+	return c.client.Put(path, Arguments{"due": due.Format(time.RFC3339)}, c)
+}
+
+// RemoveDueDate clears the card's due date.
+func (c *Card) RemoveDueDate() error {
+	path := fmt.Sprintf("cards/%s", c.ID)
+	err := c.client.Put(path, Arguments{"due": ""}, c)
+	if err == nil {
+		c.Due = nil
+	}
+	return err
+}
+
+// SetDueReminder sets the reminder of the card's due date to the given number
+// of minutes before it and updates the receiver from the response; -1 removes
+// the reminder. A reminder can only be set on a card with a due date, so
+// setting one on a card whose Due is nil returns an error without a request.
+func (c *Card) SetDueReminder(minutesBefore int) error {
+	if minutesBefore < -1 {
+		return errors.Errorf("Invalid due reminder of %d minutes for card %s", minutesBefore, c.ID)
+	}
+	if minutesBefore != -1 && c.Due == nil {
+		return errors.Errorf("Card %s has no due date to set a reminder for", c.ID)
+	}
+	path := fmt.Sprintf("cards/%s", c.ID)
+	return c.client.Put(path, Arguments{"dueReminder": strconv.Itoa(minutesBefore)}, c)
+}
+
+// SetName renames the card and updates the receiver from the response. Trello
+// derives the slug at the end of the card's URL from its name, so URL,
+// ShortURL and ShortLink are refreshed as well.
+func (c *Card) SetName(name string) error {
+	path := fmt.Sprintf("cards/%s", c.ID)
+	err := c.client.Put(path, Arguments{"name": name}, c)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to rename card %s", c.ID)
+	}
+	return nil
+}
+
+// SetStart sets the card's start date and updates the receiver from the response.
+func (c *Card) SetStart(start time.Time) error {
+	path := fmt.Sprintf("cards/%s", c.ID)
+	return c.client.Put(path, Arguments{"start": start.Format(time.RFC3339)}, c)
+}
+
+// RemoveStart clears the card's start date.
+func (c *Card) RemoveStart() error {
+	path := fmt.Sprintf("cards/%s", c.ID)
+	err := c.client.Put(path, Arguments{"start": ""}, c)
+	if err == nil {
+		c.Start = nil
+	}
+	return err
+}
+
+// SetTemplate marks the card as a template card, or turns a template back
+// into a regular card, and updates the receiver from the response.
+func (c *Card) SetTemplate(isTemplate bool) error {
+	path := fmt.Sprintf("cards/%s", c.ID)
+	return c.client.Put(path, Arguments{"isTemplate": strconv.FormatBool(isTemplate)}, c)
+}
+
 // Archive archives the card.
 func (c *Card) Archive() error {
 	return c.Update(Arguments{"closed": "true"})
@@ -329,6 +737,149 @@ func (l *List) AddCard(card *Card, extraArgs ...Arguments) error {
 	return err
 }
 
+// keyedMutex hands out a mutex per key. A key's mutex only exists while it's
+// held or waited for, so keys which are done with don't accumulate.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedMutexEntry
+}
+
+type keyedMutexEntry struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks the mutex of key and returns the function unlocking it.
+func (k *keyedMutex) lock(key string) (unlock func()) {
+	k.mu.Lock()
+	entry, ok := k.locks[key]
+	if !ok {
+		entry = &keyedMutexEntry{}
+		k.locks[key] = entry
+	}
+	entry.refs++
+	k.mu.Unlock()
+
+	entry.Lock()
+	return func() {
+		entry.Unlock()
+		k.mu.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// AddCardIdempotent adds card to the receiver list like AddCard, unless the
+// list already has a card carrying idempotencyKey, in which case card is
+// overwritten with the existing one and nothing is created. The key is kept
+// as a " [idempotencyKey]" suffix of the card name, so retrying a failed sync
+// with the same key never creates the card twice. Archived cards count as
+// existing. Calls for the same list and key made through the same Client (or
+// clients derived from it with WithContext) are serialized; the check and
+// creation aren't atomic against other clients or processes. Clients which
+// weren't created by NewClient don't serialize the calls.
+func (l *List) AddCardIdempotent(card *Card, idempotencyKey string, extraArgs ...Arguments) error {
+	if idempotencyKey == "" {
+		return errors.Errorf("Can't add a card to list %s without an idempotency key", l.ID)
+	}
+
+	if locks := l.client.idempotencyLocks; locks != nil {
+		defer locks.lock(l.ID + "/" + idempotencyKey)()
+	}
+
+	marker := " [" + idempotencyKey + "]"
+	cards, err := l.GetCards(Arguments{"filter": "all"})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to look up card '%s' in list %s", idempotencyKey, l.ID)
+	}
+	for _, existing := range cards {
+		if strings.HasSuffix(existing.Name, marker) {
+			*card = *existing
+			return nil
+		}
+	}
+
+	if !strings.HasSuffix(card.Name, marker) {
+		card.Name += marker
+	}
+	return l.AddCard(card, extraArgs...)
+}
+
+// AddCardFromTemplate creates a card named name in the receiver list from
+// the template card templateCardID. Everything is kept from the template
+// (checklists, custom field values, labels, ...), unless Arguments such as
+// Arguments{"keepFromSource": "checklists"} say otherwise.
+func (l *List) AddCardFromTemplate(templateCardID, name string, extraArgs ...Arguments) (*Card, error) {
+	args := Arguments{
+		"idList":         l.ID,
+		"idCardSource":   templateCardID,
+		"keepFromSource": "all",
+		"name":           name,
+	}
+	args.flatten(extraArgs)
+
+	card := Card{}
+	err := l.client.Post("cards", args, &card)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating card from template %s in list %s", templateCardID, l.ID)
+	}
+	card.SetClient(l.client)
+	return &card, nil
+}
+
+// AddMirrorCard creates a mirror of the card with the given id in the receiver
+// List and returns it. The source is sent as mirrorSourceId; if Trello expects
+// it under another name, Arguments{"sourceField": "..."} names the parameter
+// to use instead (sourceField itself isn't sent).
+func (l *List) AddMirrorCard(sourceCardID string, extraArgs ...Arguments) (*Card, error) {
+	args := Arguments{
+		"idList":   l.ID,
+		"cardRole": "mirror",
+	}
+	args.flatten(extraArgs)
+	sourceField := "mirrorSourceId"
+	if field, ok := args["sourceField"]; ok {
+		sourceField = field
+		delete(args, "sourceField")
+	}
+	args[sourceField] = sourceCardID
+
+	card := Card{}
+	err := l.client.Post("cards", args, &card)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating mirror of card %s in list %s", sourceCardID, l.ID)
+	}
+	card.SetClient(l.client)
+	return &card, nil
+}
+
+// IsMirror returns true if the card mirrors another card.
+func (c *Card) IsMirror() bool {
+	return c.CardRole == "mirror" || c.IDMirrorSource != ""
+}
+
+// IsSeparator returns true if the card is a separator, i.e. a card whose name
+// is only dashes that Trello renders as a horizontal rule.
+func (c *Card) IsSeparator() bool {
+	return c.CardRole == "separator"
+}
+
+// IsLink returns true if the card is rendered as a link, either to a URL or
+// to another board. Cards created before Trello introduced roles have an
+// empty CardRole and aren't links.
+func (c *Card) IsLink() bool {
+	return c.CardRole == "link" || c.CardRole == "board"
+}
+
+// MirrorSourceID returns the id of the card mirrored by the card, or an empty
+// string if it isn't a mirror card.
+func (c *Card) MirrorSourceID() string {
+	return c.IDMirrorSource
+}
+
 // CopyToList takes a list id and Arguments and returns the matching Card.
 // The following Arguments are supported.
 //
@@ -352,6 +903,72 @@ func (c *Card) CopyToList(listID string, extraArgs ...Arguments) (*Card, error)
 	return &newCard, err
 }
 
+// CopyToListWithOptions copies the card to the list with the given id,
+// keeping the attributes named in keepFromSource (e.g. "checklists",
+// "attachments", "comments", "due", "members" or just "all"). An empty
+// keepFromSource copies Trello's default set. The returned Card is the one
+// built from the server response, so copied checklists carry their new IDs.
+func (c *Card) CopyToListWithOptions(listID string, keepFromSource []string, extraArgs ...Arguments) (*Card, error) {
+	args := flattenArguments(extraArgs)
+	if len(keepFromSource) > 0 {
+		args["keepFromSource"] = strings.Join(keepFromSource, ",")
+	}
+	return c.CopyToList(listID, args)
+}
+
+// CopyWithComments copies the card to the list with the given id like
+// CopyToList, and makes sure the copy carries the comments of the receiver.
+// Trello copies them itself unless Arguments["keepFromSource"] is given
+// without "comments" or "all". In that case every comment is added again to
+// the copy, oldest first, prefixed with its original author and date. The
+// comments are posted by the client's member, so they can't keep their
+// original authorship.
+func (c *Card) CopyWithComments(listID string, extraArgs ...Arguments) (*Card, error) {
+	args := flattenArguments(extraArgs)
+	if keepsComments(args["keepFromSource"]) {
+		return c.CopyToList(listID, args)
+	}
+
+	comments, err := c.GetCommentsResolved()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the comments of card %s", c.ID)
+	}
+	newCard, err := c.CopyToList(listID, args)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Date.Before(comments[j].Date)
+	})
+	for _, comment := range comments {
+		author := comment.AuthorName
+		if author == "" {
+			author = comment.IDMemberCreator
+		}
+		text := fmt.Sprintf("Originally posted by %s on %s:\n\n%s", author, comment.Date.UTC().Format("2006-01-02 15:04 MST"), comment.Text)
+		if _, err := newCard.AddComment(text); err != nil {
+			return newCard, errors.Wrapf(err, "Failed to copy comment %s of card %s", comment.ID, c.ID)
+		}
+	}
+	return newCard, nil
+}
+
+// keepsComments tells whether Trello copies the comments of a card copied
+// with the given keepFromSource, which defaults to "all".
+func keepsComments(keepFromSource string) bool {
+	if keepFromSource == "" {
+		return true
+	}
+	for _, kept := range strings.Split(keepFromSource, ",") {
+		switch strings.TrimSpace(kept) {
+		case "all", "comments":
+			return true
+		}
+	}
+	return false
+}
+
 // AddComment takes a comment string and Arguments and adds the comment to the card.
 func (c *Card) AddComment(comment string, extraArgs ...Arguments) (*Action, error) {
 	args := Arguments{
@@ -368,11 +985,15 @@ func (c *Card) AddComment(comment string, extraArgs ...Arguments) (*Action, erro
 }
 
 // AddURLAttachment takes an Attachment and adds it to the card.
+// Attribute currently supported as extra argument: setCover. With
+// Arguments{"setCover": "true"} an image attachment immediately becomes the
+// cover of the card.
 func (c *Card) AddURLAttachment(attachment *Attachment, extraArgs ...Arguments) error {
 	path := fmt.Sprintf("cards/%s/attachments", c.ID)
 	args := Arguments{
-		"url":  attachment.URL,
-		"name": attachment.Name,
+		"url":      attachment.URL,
+		"name":     attachment.Name,
+		"setCover": "false",
 	}
 	args.flatten(extraArgs)
 	err := c.client.Post(path, args, &attachment)
@@ -383,6 +1004,32 @@ func (c *Card) AddURLAttachment(attachment *Attachment, extraArgs ...Arguments)
 
 }
 
+// AddURLAttachments adds every Attachment to the card like AddURLAttachment,
+// at most Client.BatchConcurrency at a time. The attachments are updated in
+// place, so the ones which were added have their ID set. If adding any of them
+// fails, the first failure (in the order of attachments) is returned, stating
+// how many of them were added.
+func (c *Card) AddURLAttachments(attachments []*Attachment, extraArgs ...Arguments) error {
+	errs := c.client.forEachConcurrently(len(attachments), func(i int) error {
+		return c.AddURLAttachment(attachments[i], extraArgs...)
+	})
+
+	var first error
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	if first != nil {
+		return errors.Wrapf(first, "Added %d of %d attachments to card %s", len(attachments)-failed, len(attachments), c.ID)
+	}
+	return nil
+}
+
 // GetAttachments returns all attachments for a card
 func (c *Card) GetAttachments(args Arguments) (attachments []*Attachment, err error) {
 	path := fmt.Sprintf("cards/%s/attachments", c.ID)
@@ -519,11 +1166,7 @@ func (c *Card) CreatorMemberID() (string, error) {
 // ContainsCopyOfCard accepts a card id and Arguments and returns true
 // if the receiver Board contains a Card with the id.
 func (b *Board) ContainsCopyOfCard(cardID string, extraArgs ...Arguments) (bool, error) {
-	args := Arguments{
-		"filter": "copyCard",
-	}
-	args.flatten(extraArgs)
-	actions, err := b.GetActions(args)
+	actions, err := b.GetActionsSince(time.Time{}, []string{"copyCard"}, extraArgs...)
 	if err != nil {
 		err := errors.Wrapf(err, "GetCards() failed inside ContainsCopyOf() for board '%s' and card '%s'.", b.ID, cardID)
 		return false, err
@@ -538,10 +1181,10 @@ func (b *Board) ContainsCopyOfCard(cardID string, extraArgs ...Arguments) (bool,
 
 // GetCard receives a card id and Arguments and returns the card if found
 // with the credentials given for the receiver Client. Returns an error
-// otherwise.
+// otherwise. The short link of a card works as well as its id.
 func (c *Client) GetCard(cardID string, extraArgs ...Arguments) (card *Card, err error) {
 	args := flattenArguments(extraArgs)
-	path := fmt.Sprintf("cards/%s", cardID)
+	path := fmt.Sprintf("cards/%s", url.PathEscape(cardID))
 	err = c.Get(path, args, &card)
 	if card != nil {
 		card.client = c
@@ -549,39 +1192,309 @@ func (c *Client) GetCard(cardID string, extraArgs ...Arguments) (card *Card, err
 	return card, err
 }
 
+// shortLinkPattern matches the short link of a card.
+var shortLinkPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// ParseCardShortLink takes the URL of a card, like
+// https://trello.com/c/GRsvY3vZ/4-learn-about-the-trello-api, and returns its
+// short link (GRsvY3vZ). The slug, query string, fragment and scheme are
+// optional.
+func ParseCardShortLink(cardURL string) (string, error) {
+	raw := strings.TrimSpace(cardURL)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", errors.Wrapf(err, "Invalid card URL '%s'", cardURL)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "c" || !shortLinkPattern.MatchString(segments[1]) {
+		return "", errors.Errorf("'%s' isn't the URL of a card", cardURL)
+	}
+	return segments[1], nil
+}
+
+// GetCardByURL takes the URL of a card and Arguments and returns the card, see
+// ParseCardShortLink for the supported URLs.
+func (c *Client) GetCardByURL(cardURL string, extraArgs ...Arguments) (*Card, error) {
+	shortLink, err := ParseCardShortLink(cardURL)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCard(shortLink, extraArgs...)
+}
+
+// batchSize is the maximum number of routes Trello accepts per batch request.
+const batchSize = 10
+
+// GetCardsByIDs fetches the cards with the given ids using Trello's batch
+// endpoint, up to ten cards per request. At most Client.BatchConcurrency
+// batch requests are in flight at once. Arguments such as fields or
+// customFieldItems are applied to every card. The cards are returned in the
+// order of ids. Cards which don't exist are skipped and reported by a
+// *CardsNotFoundError (which satisfies IsNotFound) next to the found cards.
+func (c *Client) GetCardsByIDs(ids []string, extraArgs ...Arguments) ([]*Card, error) {
+	query := ""
+	if args := flattenArguments(extraArgs); len(args) > 0 {
+		query = "?" + args.ToURLValues().Encode()
+	}
+
+	found := make([]*Card, len(ids))
+	errs := c.forEachConcurrently((len(ids)+batchSize-1)/batchSize, func(batch int) error {
+		start := batch * batchSize
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		return c.getCardsBatch(ids[start:end], query, found[start:end])
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cards := make([]*Card, 0, len(ids))
+	var missing []string
+	for i, card := range found {
+		if card == nil {
+			missing = append(missing, ids[i])
+			continue
+		}
+		cards = append(cards, card)
+	}
+	if len(missing) > 0 {
+		return cards, &CardsNotFoundError{IDs: missing}
+	}
+	return cards, nil
+}
+
+// getCardsBatch fetches the cards with the given ids in a single batch
+// request and stores them in cards, leaving nil for every card not found.
+func (c *Client) getCardsBatch(ids []string, query string, cards []*Card) error {
+	urls := make([]string, len(ids))
+	for i, id := range ids {
+		urls[i] = "/cards/" + url.PathEscape(id) + query
+	}
+
+	var responses []map[string]json.RawMessage
+	err := c.Get("batch", Arguments{"urls": strings.Join(urls, ",")}, &responses)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get batch of cards %s", strings.Join(ids, ","))
+	}
+	if len(responses) != len(ids) {
+		return errors.Errorf("Expected %d batch responses, got %d", len(ids), len(responses))
+	}
+
+	for i, response := range responses {
+		if body, ok := response["200"]; ok {
+			err = json.Unmarshal(body, &cards[i])
+			if err != nil {
+				return errors.Wrapf(err, "Failed to decode card %s", ids[i])
+			}
+			cards[i].SetClient(c)
+			continue
+		}
+		if _, ok := response["404"]; ok || string(response["statusCode"]) == "404" {
+			continue
+		}
+		return errors.Errorf("Failed to get card %s in batch: %s", ids[i], response["message"])
+	}
+	return nil
+}
+
+// Refresh takes Arguments, re-fetches the receiver card and overwrites all
+// of its fields with the server's values, so pointers to the card stay valid.
+// Use Arguments such as {"members": "true", "customFieldItems": "true"} to
+// reload nested resources as well. The client is kept.
+func (c *Card) Refresh(extraArgs ...Arguments) error {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("cards/%s", c.ID)
+	fresh := Card{}
+	err := c.client.Get(path, args, &fresh)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to refresh card %s", c.ID)
+	}
+	client := c.client
+	*c = fresh
+	c.SetClient(client)
+	return nil
+}
+
 // GetCards takes Arguments and retrieves all Cards on a Board as slice or returns error.
+// Trello returns the cards in pages, which are requested until an empty one.
+// If a page fails, the cards of the pages before are returned with the error.
 func (b *Board) GetCards(extraArgs ...Arguments) (cards []*Card, err error) {
+	err = b.eachCardPage(flattenArguments(extraArgs), func(page []*Card) error {
+		cards = append(cards, page...)
+		return nil
+	})
+	return
+}
+
+// eachCardPage fetches the cards of the receiver Board page by page, going
+// back in time with the before argument, and hands each page to fn. It stops
+// at the first error, be it from Trello or from fn.
+func (b *Board) eachCardPage(args Arguments, fn func(cards []*Card) error) error {
+	path := fmt.Sprintf("boards/%s/cards", b.ID)
+	for {
+		var cards []*Card
+		if err := b.client.Get(path, args, &cards); err != nil {
+			return err
+		}
+		if len(cards) == 0 {
+			return nil
+		}
+		for _, card := range cards {
+			card.SetClient(b.client)
+		}
+		if err := fn(cards); err != nil {
+			return err
+		}
+		args["before"] = earliestCardID(cards)
+	}
+}
+
+// GetCardsInto works like GetCards, but decodes the cards into *dst instead
+// of a new slice. The capacity of *dst and the Cards it points to are reused,
+// which saves allocations when a board is polled repeatedly with the same
+// slice. Reused Cards are reset before decoding and the entries past the new
+// length are cleared, so nothing of the previous fetch is left behind; cards
+// from a previous call must therefore not be kept elsewhere.
+func (b *Board) GetCardsInto(dst *[]*Card, extraArgs ...Arguments) error {
 	args := flattenArguments(extraArgs)
 	path := fmt.Sprintf("boards/%s/cards", b.ID)
 
-	err = b.client.Get(path, args, &cards)
-
-	// Naive implementation would return here. To make sure we get all
-	// cards, we begin
-	if len(cards) > 0 {
-		moreCards := true
-		for moreCards {
-			nextCardBatch := make([]*Card, 0)
-			args["before"] = earliestCardID(cards)
-			err = b.client.Get(path, args, &nextCardBatch)
-			if len(nextCardBatch) > 0 {
-				cards = append(cards, nextCardBatch...)
-			} else {
-				moreCards = false
+	cards := (*dst)[:cap(*dst)]
+	for _, card := range cards {
+		if card != nil {
+			*card = Card{}
+		}
+	}
+	cards = cards[:0]
+	err := b.client.Get(path, args, &cards)
+
+	for err == nil && len(cards) > 0 {
+		var nextCardBatch []*Card
+		args["before"] = earliestCardID(cards)
+		err = b.client.Get(path, args, &nextCardBatch)
+		if len(nextCardBatch) == 0 {
+			break
+		}
+		cards = append(cards, nextCardBatch...)
+	}
+
+	stale := cards[len(cards):cap(cards)]
+	for i := range stale {
+		stale[i] = nil
+	}
+	for _, card := range cards {
+		card.SetClient(b.client)
+	}
+	*dst = cards
+	return err
+}
+
+// CardsByMember takes Arguments, fetches all cards on the receiver Board
+// (with their members) and groups them by the IDs of the members assigned to
+// them. A card assigned to several members appears under each of them, cards
+// assigned to no one are listed under the empty string key.
+func (b *Board) CardsByMember(extraArgs ...Arguments) (map[string][]*Card, error) {
+	args := flattenArguments(extraArgs)
+	args["members"] = "true"
+	cards, err := b.GetCards(args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the cards of board %s", b.ID)
+	}
+
+	byMember := map[string][]*Card{}
+	for _, card := range cards {
+		if len(card.IDMembers) == 0 {
+			byMember[""] = append(byMember[""], card)
+			continue
+		}
+		for _, memberID := range card.IDMembers {
+			byMember[memberID] = append(byMember[memberID], card)
+		}
+	}
+	return byMember, nil
+}
+
+// GetCardsStartingBetween takes two times and Arguments, fetches all cards on
+// the receiver Board and returns those whose start date lies between from and
+// to, both included, ordered by their start date. Cards without a start date
+// are left out.
+func (b *Board) GetCardsStartingBetween(from, to time.Time, extraArgs ...Arguments) ([]*Card, error) {
+	cards, err := b.GetCards(extraArgs...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the cards of board %s", b.ID)
+	}
+
+	starting := make([]*Card, 0, len(cards))
+	for _, card := range cards {
+		if card.Start == nil || card.Start.Before(from) || card.Start.After(to) {
+			continue
+		}
+		starting = append(starting, card)
+	}
+	sort.SliceStable(starting, func(i, j int) bool { return starting[i].Start.Before(*starting[j].Start) })
+	return starting, nil
+}
+
+// GetCardsByLabel takes a label id and Arguments, fetches all cards on the
+// receiver Board and returns those which carry the label. Trello can't filter
+// cards by label, so all cards are fetched.
+func (b *Board) GetCardsByLabel(labelID string, extraArgs ...Arguments) ([]*Card, error) {
+	cards, err := b.GetCards(extraArgs...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the cards of board %s", b.ID)
+	}
+
+	labeled := make([]*Card, 0, len(cards))
+	for _, card := range cards {
+		for _, id := range card.IDLabels {
+			if id == labelID {
+				labeled = append(labeled, card)
+				break
 			}
 		}
 	}
+	return labeled, nil
+}
 
-	for i := range cards {
-		cards[i].SetClient(b.client)
+// GetCardsByLabelName works like GetCardsByLabel, but takes the name of the
+// label. It returns an error if the board has no label or several labels with
+// that name, in which case GetCardsByLabel has to be used.
+func (b *Board) GetCardsByLabelName(name string, extraArgs ...Arguments) ([]*Card, error) {
+	labels, err := b.GetLabels()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the labels of board %s", b.ID)
 	}
 
-	return
+	var ids []string
+	for _, label := range labels {
+		if label.Name == name {
+			ids = append(ids, label.ID)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return nil, errors.Errorf("Board %s has no label named '%s'", b.ID, name)
+	case 1:
+		return b.GetCardsByLabel(ids[0], extraArgs...)
+	default:
+		return nil, errors.Errorf("Board %s has %d labels named '%s' (%s)", b.ID, len(ids), name, strings.Join(ids, ", "))
+	}
 }
 
-// GetCards retrieves all Cards in a List or an error if something goes wrong.
+// GetCards retrieves the Cards in a List or an error if something goes wrong.
+// Only open cards are returned by default, Arguments{"filter": "closed"} or
+// Arguments{"filter": "all"} include the archived ones.
 func (l *List) GetCards(extraArgs ...Arguments) (cards []*Card, err error) {
-	args := flattenArguments(extraArgs)
+	args := Arguments{"filter": "open"}
+	args.flatten(extraArgs)
 	path := fmt.Sprintf("lists/%s/cards", l.ID)
 	err = l.client.Get(path, args, &cards)
 	for i := range cards {
@@ -590,6 +1503,103 @@ func (l *List) GetCards(extraArgs ...Arguments) (cards []*Card, err error) {
 	return
 }
 
+// GetCardsModifiedSince returns the cards of the receiver List, archived ones
+// included, which were the subject of an action on the list after since. The
+// actions are read from the list, so only the last 1000 actions are taken
+// into account. Cards which have left the list since are skipped. Arguments
+// are passed along to GetCards.
+func (l *List) GetCardsModifiedSince(since time.Time, extraArgs ...Arguments) ([]*Card, error) {
+	actions, err := l.GetActions(Arguments{
+		"since": since.UTC().Format(time.RFC3339),
+		"limit": "1000",
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the actions of list %s", l.ID)
+	}
+
+	modified := map[string]bool{}
+	for _, action := range actions {
+		if action.Data != nil && action.Data.Card != nil {
+			modified[action.Data.Card.ID] = true
+		}
+	}
+	if len(modified) == 0 {
+		return []*Card{}, nil
+	}
+
+	args := Arguments{"filter": "all"}
+	args.flatten(extraArgs)
+	cards, err := l.GetCards(args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the cards of list %s", l.ID)
+	}
+
+	result := make([]*Card, 0, len(modified))
+	for _, card := range cards {
+		if modified[card.ID] {
+			result = append(result, card)
+		}
+	}
+	return result, nil
+}
+
+// GetCards takes Arguments and returns the cards of the receiver Member.
+func (m *Member) GetCards(extraArgs ...Arguments) (cards []*Card, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("members/%s/cards", m.ID)
+	err = m.client.Get(path, args, &cards)
+	for i := range cards {
+		cards[i].SetClient(m.client)
+	}
+	return
+}
+
+// CardsByBoard takes Arguments, fetches the cards of the receiver Member in a
+// single request and groups them by the IDs of their boards. The idBoard
+// field is always requested, even if Arguments{"fields": "..."} doesn't list it.
+func (m *Member) CardsByBoard(extraArgs ...Arguments) (map[string][]*Card, error) {
+	args := flattenArguments(extraArgs)
+	args.ensureField("idBoard")
+	cards, err := m.GetCards(args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the cards of member %s", m.ID)
+	}
+
+	byBoard := map[string][]*Card{}
+	for _, card := range cards {
+		byBoard[card.IDBoard] = append(byBoard[card.IDBoard], card)
+	}
+	return byBoard, nil
+}
+
+// GetCardsDueBefore fetches the cards of the receiver Member and returns those
+// due before the cutoff, earliest first. Cards marked as done are left out
+// unless Arguments{"includeDueComplete": "true"} is given; this argument isn't
+// sent to Trello, all other Arguments are.
+func (m *Member) GetCardsDueBefore(cutoff time.Time, extraArgs ...Arguments) ([]*Card, error) {
+	args := flattenArguments(extraArgs)
+	includeDueComplete := args["includeDueComplete"] == "true"
+	delete(args, "includeDueComplete")
+
+	cards, err := m.GetCards(args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the cards of member %s", m.ID)
+	}
+
+	due := make([]*Card, 0, len(cards))
+	for _, card := range cards {
+		if card.Due == nil || !card.Due.Before(cutoff) {
+			continue
+		}
+		if card.DueComplete && !includeDueComplete {
+			continue
+		}
+		due = append(due, card)
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].Due.Before(*due[j].Due) })
+	return due, nil
+}
+
 func earliestCardID(cards []*Card) string {
 	if len(cards) == 0 {
 		return ""
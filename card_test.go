@@ -6,9 +6,17 @@
 package trello
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 func TestCardCreatedAt(t *testing.T) {
@@ -39,6 +47,32 @@ func TestGetCardsOnBoard(t *testing.T) {
 	}
 }
 
+func TestBoardGetCardsPageError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("before") != "" {
+			http.Error(rw, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+		rw.Write([]byte(`[{"id": "5fb00c0a7b6e2d3c4b500002", "name": "Two"}, {"id": "5fb00c0a7b6e2d3c4b500001", "name": "One"}]`))
+	}))
+	defer server.Close()
+
+	board := &Board{ID: "4ed7e27fe6abb2517a21383d"}
+	board.SetClient(testClient())
+	board.client.BaseURL = server.URL
+	board.client.MaxRetries = -1
+
+	cards, err := board.GetCards()
+	if !IsRateLimit(err) {
+		t.Errorf("Expected the error of the second page, got %v", err)
+	}
+	if len(cards) != 2 || requests != 2 {
+		t.Errorf("Expected the 2 cards of the first page after 2 requests, got %d cards after %d requests", len(cards), requests)
+	}
+}
+
 func TestGetCardsInList(t *testing.T) {
 	list := testList(t)
 
@@ -92,6 +126,237 @@ func TestCardsCustomFields(t *testing.T) {
 	}
 }
 
+func TestCardCustomFieldItem(t *testing.T) {
+	list := testList(t)
+	server := NewMockResponder(t, "cards", "list-cards-api-example.json")
+	defer server.Close()
+	list.client.BaseURL = server.URL()
+
+	cards, err := list.GetCards(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	card := cards[0]
+
+	item, ok := card.CustomFieldItem("5a6a23abf958725e1ac86c21")
+	if !ok || item.IDValue != "5a6a23abf958725e1ac86c23" {
+		t.Errorf("Expected the item of field 5a6a23abf958725e1ac86c21, got %v", item)
+	}
+	if item, ok := card.CustomFieldItem("5a6a23abf958725e1ac86cff"); ok || item != nil {
+		t.Errorf("Didn't expect an item for an absent field, got %v", item)
+	}
+
+	customFields := testBoardCustomFields(t)
+	for _, field := range customFields {
+		if field.Name != "Field1" {
+			continue
+		}
+		value, ok := card.CustomFieldValue(field)
+		if !ok || value != "F1 1st opt" {
+			t.Errorf("Expected Field1 to resolve to 'F1 1st opt', got %v", value)
+		}
+	}
+
+	if value, ok := card.CustomFieldValue(&CustomField{ID: "5a6a23abf958725e1ac86cff"}); ok {
+		t.Errorf("Didn't expect a value for an absent field, got %v", value)
+	}
+}
+
+func TestCardCustomFieldValue(t *testing.T) {
+	estimate := &CustomField{ID: "5a98670bd6afbd6de1c8c361", Name: "Estimate", Type: "number"}
+	priority := &CustomField{ID: "5a6a23abf958725e1ac86c21", Name: "Priority", Type: "list", Options: []*CustomFieldOption{{ID: "5a6a23abf958725e1ac86c22"}}}
+	priority.Options[0].Value.Text = "High"
+
+	card := &Card{CustomFieldItems: []*CustomFieldItem{
+		{IDCustomField: estimate.ID, Value: NewCustomFieldValue(3)},
+		{IDCustomField: priority.ID, IDValue: "5a6a23abf958725e1ac86c22"},
+	}}
+
+	if value, ok := card.CustomFieldValue(estimate); !ok || value != 3 {
+		t.Errorf("Expected the estimate 3, got %v", value)
+	}
+	if value, ok := card.CustomFieldValue(priority); !ok || value != "High" {
+		t.Errorf("Expected the priority 'High', got %v", value)
+	}
+
+	card.CustomFieldItems[1].IDValue = "5a6a23abf958725e1ac86cff"
+	if value, ok := card.CustomFieldValue(priority); ok {
+		t.Errorf("Didn't expect a value for an unknown option, got %v", value)
+	}
+}
+
+func TestBoardGetCardsInto(t *testing.T) {
+	responses := []string{
+		`[{"id": "5fb00c0a7b6e2d3c4b500003", "name": "Three", "desc": "Only in the first fetch"},
+		  {"id": "5fb00c0a7b6e2d3c4b500002", "name": "Two"},
+		  {"id": "5fb00c0a7b6e2d3c4b500001", "name": "One"}]`,
+		`[{"id": "5fb00c0a7b6e2d3c4b500005", "name": "Five"},
+		  {"id": "5fb00c0a7b6e2d3c4b500004", "name": "Four"}]`,
+	}
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("before") != "" {
+			rw.Write([]byte(`[]`))
+			return
+		}
+		rw.Write([]byte(responses[fetches]))
+		fetches++
+	}))
+	defer server.Close()
+
+	board := &Board{ID: "4ed7e27fe6abb2517a21383d"}
+	board.SetClient(testClient())
+	board.client.BaseURL = server.URL
+
+	var cards []*Card
+	if err := board.GetCardsInto(&cards); err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 3 || cards[0].Desc != "Only in the first fetch" {
+		t.Fatalf("Expected the 3 cards of the first fetch, got %d", len(cards))
+	}
+	first, backing := cards[0], &cards[:cap(cards)][0]
+
+	otherClient := testClient()
+	otherClient.BaseURL = server.URL
+	board.SetClient(otherClient)
+	if err := board.GetCardsInto(&cards); err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 || cards[0].Name != "Five" || cards[1].Name != "Four" {
+		t.Fatalf("Expected the 2 cards of the second fetch, got %d", len(cards))
+	}
+	if &cards[:cap(cards)][0] != backing || cards[0] != first {
+		t.Error("Expected the slice and its cards to be reused")
+	}
+	if cards[0].Desc != "" {
+		t.Errorf("Expected no stale description on a reused card, got '%s'", cards[0].Desc)
+	}
+	if stale := cards[:cap(cards)][2]; stale != nil {
+		t.Errorf("Expected no stale entry past the new length, got card %s", stale.ID)
+	}
+	for _, card := range cards {
+		if card.client != otherClient {
+			t.Errorf("Expected card %s to carry the board's current client", card.ID)
+		}
+	}
+}
+
+// BenchmarkBoardGetCardsInto compares polling a board with GetCards with
+// polling it with GetCardsInto and a reused slice.
+// Run with: go test -run XXX -bench GetCardsInto
+func BenchmarkBoardGetCardsInto(b *testing.B) {
+	data := largeCardsResponse(1000)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("before") != "" {
+			rw.Write([]byte(`[]`))
+			return
+		}
+		rw.Write(data)
+	}))
+	defer server.Close()
+
+	board := &Board{ID: "4ed7e27fe6abb2517a21383d"}
+	board.SetClient(testClient())
+	board.client.BaseURL = server.URL
+
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := board.GetCards(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("reused", func(b *testing.B) {
+		b.ReportAllocs()
+		var cards []*Card
+		for i := 0; i < b.N; i++ {
+			if err := board.GetCardsInto(&cards); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestBoardGetCardsStartingBetween(t *testing.T) {
+	board := &Board{ID: "60400c0a7b6e2d3c4b5a00b0"}
+	board.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	board.client.BaseURL = server.URL()
+
+	berlin := time.FixedZone("CET", 60*60)
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, berlin)
+	to := time.Date(2021, 3, 7, 23, 59, 59, 0, berlin)
+
+	cards, err := board.GetCardsStartingBetween(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"60400c0a7b6e2d3c4b5a0002", "60400c0a7b6e2d3c4b5a0006", "60400c0a7b6e2d3c4b5a0004"}
+	if len(cards) != len(expected) {
+		t.Fatalf("Expected %d cards, got %d", len(expected), len(cards))
+	}
+	for i, id := range expected {
+		if cards[i].ID != id {
+			t.Errorf("Expected card %s at position %d, got %s", id, i, cards[i].ID)
+		}
+	}
+}
+
+func TestBoardGetCardsByLabel(t *testing.T) {
+	board := &Board{ID: "60400c0a7b6e2d3c4b5a00b0"}
+	board.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	board.client.BaseURL = server.URL()
+
+	cards, err := board.GetCardsByLabel("60400c0a7b6e2d3c4b5a0201")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"60400c0a7b6e2d3c4b5a0001", "60400c0a7b6e2d3c4b5a0003", "60400c0a7b6e2d3c4b5a0006"}
+	if len(cards) != len(expected) {
+		t.Fatalf("Expected %d cards, got %d", len(expected), len(cards))
+	}
+	for i, id := range expected {
+		if cards[i].ID != id {
+			t.Errorf("Expected card %s at position %d, got %s", id, i, cards[i].ID)
+		}
+		if cards[i].client == nil {
+			t.Errorf("Expected card %s to have a client", cards[i].ID)
+		}
+	}
+}
+
+func TestBoardGetCardsByLabelName(t *testing.T) {
+	board := &Board{ID: "60400c0a7b6e2d3c4b5a00b0"}
+	board.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	board.client.BaseURL = server.URL()
+
+	cards, err := board.GetCardsByLabelName("Feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 3 {
+		t.Errorf("Expected 3 cards labeled 'Feature', got %d", len(cards))
+	}
+
+	_, err = board.GetCardsByLabelName("Bug")
+	if err == nil || !strings.Contains(err.Error(), "2 labels named 'Bug'") {
+		t.Errorf("Expected an error for the ambiguous name, got %v", err)
+	}
+
+	_, err = board.GetCardsByLabelName("Chore")
+	if err == nil || !strings.Contains(err.Error(), "no label named 'Chore'") {
+		t.Errorf("Expected an error for an unknown name, got %v", err)
+	}
+}
+
 func TestBoardContainsCopyOfCard(t *testing.T) {
 	board := testBoard(t)
 
@@ -261,102 +526,1439 @@ func TestArchiveUnarchive(t *testing.T) {
 	server.Close()
 }
 
-func TestCopyCardToList(t *testing.T) {
-	c := testCard(t)
+func TestCardSetPos(t *testing.T) {
+	for _, tc := range []struct {
+		pos      interface{}
+		expected string
+	}{
+		{32768.5, "32768.5"},
+		{16384, "16384"},
+		{1638400.0, "1638400"},
+		{2097152.25, "2097152.25"},
+		{"top", "top"},
+		{"bottom", "bottom"},
+	} {
+		c := testCard(t)
+		server := NewMockResponder(t, "cards", "card-posted-to-bottom-of-list.json")
+		server.AssertRequest(func(t *testing.T, r *http.Request) {
+			if r.Method != http.MethodPut {
+				t.Errorf("Expected a PUT, got %s", r.Method)
+			}
+			if r.URL.Query().Get("pos") != tc.expected {
+				t.Errorf("Expected pos '%s', got '%s'", tc.expected, r.URL.Query().Get("pos"))
+			}
+		})
+		c.client.BaseURL = server.URL()
+
+		err := c.SetPos(tc.pos)
+		if err != nil {
+			t.Error(err)
+		}
+		if c.Pos != 32768 {
+			t.Errorf("Expected card to pick up Pos from the response, got %.2f", c.Pos)
+		}
+		server.Close()
+	}
+}
 
-	server := NewMockResponder(t, "cards", "card-copied.json")
+func TestCardSetPosInvalid(t *testing.T) {
+	c := testCard(t)
+	server := NewMockResponder(t, "cards", "card-posted-to-bottom-of-list.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		t.Errorf("Expected no request for an invalid position, got %s %s", r.Method, r.URL)
+	})
 	defer server.Close()
 	c.client.BaseURL = server.URL()
 
-	newCard, err := c.CopyToList("57f03a022cd45c863ca581f1", Defaults())
-	if err != nil {
-		t.Error(err)
+	for _, pos := range []interface{}{"middle", -1, 0.0, true} {
+		if err := c.SetPos(pos); err == nil {
+			t.Errorf("Expected SetPos(%v) to fail", pos)
+		}
 	}
+}
 
-	if newCard.ID == c.ID {
-		t.Errorf("New card should have a new ID: '%s'.", newCard.ID)
-	}
+func TestCardUpdateIfUnchangedConflict(t *testing.T) {
+	c := testCard(t)
+	server := NewMockResponder(t, "cards", "card-create.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected no write for a stale card, got %s", r.Method)
+		}
+	})
+	defer server.Close()
+	c.client.BaseURL = server.URL()
 
-	if newCard.Pos != 16384 {
-		t.Errorf("Expected new card to have correct Pos value. Got %.2f", newCard.Pos)
+	stale := time.Date(2016, 10, 1, 0, 0, 0, 0, time.UTC)
+	err := c.UpdateIfUnchanged(stale, Arguments{"name": "Renamed"})
+	if err == nil {
+		t.Fatal("Expected a conflict error for a stale card")
+	}
+	if !IsConflict(err) {
+		t.Errorf("Expected IsConflict() to be true, got error: %v", err)
 	}
 }
 
-func TestGetParentCard(t *testing.T) {
+func TestCardUpdateIfUnchanged(t *testing.T) {
 	c := testCard(t)
-
-	server := NewMockResponder(t)
+	server := NewMockResponder(t, "cards", "card-create.json")
+	var puts int
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method == http.MethodPut {
+			puts++
+		}
+	})
 	defer server.Close()
 	c.client.BaseURL = server.URL()
 
-	parent, err := c.GetParentCard(Defaults())
+	current, _ := time.Parse(time.RFC3339, "2016-10-05T15:11:56.325Z")
+	err := c.UpdateIfUnchanged(current, Arguments{"name": "Renamed"})
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if parent == nil {
-		t.Errorf("Problem")
+	if puts != 1 {
+		t.Errorf("Expected the card to be updated once, got %d PUTs", puts)
 	}
 }
 
-func TestGetAncestorCards(t *testing.T) {
+func TestCardSetAndRemoveDue(t *testing.T) {
 	c := testCard(t)
+	due := time.Date(2020, 7, 27, 17, 0, 0, 0, time.UTC)
 
-	server := mockDynamicPathResponse()
-	defer server.Close()
-	c.client.BaseURL = server.URL
+	server := NewMockResponder(t, "cards", "card-dates-set.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		value := r.URL.Query().Get("due")
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			t.Errorf("Expected due to be in RFC3339 format, but value was '%v'", value)
+		}
+	})
+	c.client.BaseURL = server.URL()
+	err := c.SetDue(due)
+	server.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Due == nil || !c.Due.Equal(due) {
+		t.Errorf("Expected due date %v, got %v", due, c.Due)
+	}
 
-	ancestors, err := c.GetAncestorCards(Defaults())
+	server = NewMockResponder(t, "cards", "card-dates-removed.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		values, ok := r.URL.Query()["due"]
+		if !ok || values[0] != "" {
+			t.Errorf("Expected an empty due argument, got %v", values)
+		}
+	})
+	c.client.BaseURL = server.URL()
+	err = c.RemoveDueDate()
+	server.Close()
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if len(ancestors) != 1 {
-		t.Errorf("Expected 1 ancestor, got %d", len(ancestors))
+	if c.Due != nil {
+		t.Errorf("Expected due date to be removed, got %v", c.Due)
 	}
 }
 
-func TestAddMemberIdToCard(t *testing.T) {
+func TestCardSetAndRemoveStart(t *testing.T) {
 	c := testCard(t)
-	server := NewMockResponder(t, "cards", "card-add-member-response.json")
-	defer server.Close()
+	start := time.Date(2020, 7, 20, 9, 0, 0, 0, time.UTC)
 
+	server := NewMockResponder(t, "cards", "card-dates-set.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		value := r.URL.Query().Get("start")
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			t.Errorf("Expected start to be in RFC3339 format, but value was '%v'", value)
+		}
+	})
 	c.client.BaseURL = server.URL()
-	member, err := c.AddMemberID("testmemberid")
+	err := c.SetStart(start)
+	server.Close()
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if member[0].ID != "testmemberid" {
-		t.Errorf("Expected id testmemberid, got %v", member[0].ID)
+	if c.Start == nil || !c.Start.Equal(start) {
+		t.Errorf("Expected start date %v, got %v", start, c.Start)
 	}
-	if member[0].Username != "testmemberusername" {
-		t.Errorf("Expected username testmemberusername, got %v", member[0].Username)
+
+	server = NewMockResponder(t, "cards", "card-dates-removed.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		values, ok := r.URL.Query()["start"]
+		if !ok || values[0] != "" {
+			t.Errorf("Expected an empty start argument, got %v", values)
+		}
+	})
+	c.client.BaseURL = server.URL()
+	err = c.RemoveStart()
+	server.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Start != nil {
+		t.Errorf("Expected start date to be removed, got %v", c.Start)
 	}
 }
 
-func TestAddURLAttachmentToCard(t *testing.T) {
-	c := testCard(t)
-	server := NewMockResponder(t, "cards", "url-attachments.json")
+func TestCardDueFromBadge(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-badges-only.json")
 	defer server.Close()
+	c.BaseURL = server.URL()
 
-	c.client.BaseURL = server.URL()
-	attachment := Attachment{
-		Name: "Test",
-		URL:  "https://github.com/test",
+	card, err := c.GetCard("5f4d0c0a7b6e2d3c4b5a0001", Arguments{"fields": "badges"})
+	if err != nil {
+		t.Fatal(err)
 	}
-	err := c.AddURLAttachment(&attachment)
+	if card.Due != nil {
+		t.Fatalf("Expected the due field not to be loaded, got %v", card.Due)
+	}
+
+	due, complete := card.DueFromBadge()
+	expected := time.Date(2020, 8, 31, 17, 0, 0, 0, time.UTC)
+	if due == nil || !due.Equal(expected) {
+		t.Errorf("Expected badge due %v, got %v", expected, due)
+	}
+	if !complete {
+		t.Error("Expected the badge to report the due date as complete")
+	}
+}
+
+func TestCardDueFromBadgeWithoutDue(t *testing.T) {
+	card := testCard(t)
+	due, complete := card.DueFromBadge()
+	if due != nil || complete {
+		t.Errorf("Expected no badge due date, got %v (complete: %t)", due, complete)
+	}
+}
+
+func TestCardDueStatus(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		due := now.Add(d)
+		return &due
+	}
+	tests := []struct {
+		name     string
+		due      *time.Time
+		complete bool
+		expected string
+	}{
+		{"no due date", nil, false, "none"},
+		{"no due date but complete", nil, true, "none"},
+		{"completed after the due date", at(-48 * time.Hour), true, "complete"},
+		{"completed before the due date", at(time.Hour), true, "complete"},
+		{"past due", at(-time.Minute), false, "overdue"},
+		{"due in an hour", at(time.Hour), false, "due-soon"},
+		{"due in exactly a day", at(24 * time.Hour), false, "due-soon"},
+		{"due in two days", at(48 * time.Hour), false, "upcoming"},
+	}
+
+	for _, test := range tests {
+		card := &Card{Due: test.due, DueComplete: test.complete}
+		if status := card.dueStatus(now, dueSoonWithin); status != test.expected {
+			t.Errorf("%s: expected status '%s', got '%s'", test.name, test.expected, status)
+		}
+	}
+}
+
+func TestCardIsOverdueAndDueSoon(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	soon := time.Now().Add(2 * time.Hour)
+	later := time.Now().Add(72 * time.Hour)
+	tests := []struct {
+		card    *Card
+		overdue bool
+		dueSoon bool
+		status  string
+	}{
+		{&Card{}, false, false, "none"},
+		{&Card{Due: &past}, true, false, "overdue"},
+		{&Card{Due: &past, DueComplete: true}, false, false, "complete"},
+		{&Card{Due: &soon}, false, true, "due-soon"},
+		{&Card{Due: &soon, DueComplete: true}, false, false, "complete"},
+		{&Card{Due: &later}, false, false, "upcoming"},
+	}
+
+	for i, test := range tests {
+		if overdue := test.card.IsOverdue(); overdue != test.overdue {
+			t.Errorf("%d: expected IsOverdue() %t, got %t", i, test.overdue, overdue)
+		}
+		if dueSoon := test.card.IsDueSoon(3 * time.Hour); dueSoon != test.dueSoon {
+			t.Errorf("%d: expected IsDueSoon() %t, got %t", i, test.dueSoon, dueSoon)
+		}
+		if status := test.card.DueStatus(); status != test.status {
+			t.Errorf("%d: expected DueStatus() '%s', got '%s'", i, test.status, status)
+		}
+	}
+	if !(&Card{Due: &later}).IsDueSoon(96 * time.Hour) {
+		t.Error("Expected a card due in 72 hours to be due within 96 hours")
+	}
+}
+
+func TestCardBadges(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-badges-only.json")
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	card, err := c.GetCard("5f4d0c0a7b6e2d3c4b5a0001")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if attachment.ID != "5bbce18fa4a337483b145a57" {
-		t.Errorf("Expected attachment to pick up an ID, got %v instead", attachment.ID)
+
+	if card.Badges.Comments != 1 || card.Badges.Attachments != 0 || card.Badges.Votes != 0 {
+		t.Errorf("Unexpected badge counts %+v", card.Badges)
+	}
+	if !card.Badges.Description {
+		t.Error("Expected the description badge to be set")
+	}
+	checked, total := card.ChecklistProgress()
+	if checked != 2 || total != 2 {
+		t.Errorf("Expected 2 of 2 check items checked, got %d of %d", checked, total)
 	}
 }
 
-func TestCardSetClient(t *testing.T) {
+func TestCardWithoutBadges(t *testing.T) {
 	card := Card{}
-	client := testClient()
-	card.SetClient(client)
-	if card.client == nil {
-		t.Error("Expected non-nil card.client")
+	err := json.Unmarshal([]byte(`{"id": "5f4d0c0a7b6e2d3c4b5a0002", "name": "No badges"}`), &card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Badges != (CardBadges{}) {
+		t.Errorf("Expected zero badges, got %+v", card.Badges)
+	}
+	if checked, total := card.ChecklistProgress(); checked != 0 || total != 0 {
+		t.Errorf("Expected no check items, got %d of %d", checked, total)
+	}
+}
+
+func TestCardGetCover(t *testing.T) {
+	c := testCard(t)
+	server := NewMockResponder(t, "cards", "card-image-cover.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Query().Get("fields") != "cover" {
+			t.Errorf("Expected fields 'cover', got '%s'", r.URL.Query().Get("fields"))
+		}
+	})
+	c.client.BaseURL = server.URL()
+
+	cover, err := c.GetCover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cover.IDAttachment != "5bbce18fa4a337483b145a57" || cover.Size != "full" || cover.Brightness != "dark" {
+		t.Errorf("Unexpected cover %+v", cover)
+	}
+	if cover.Color != "" || cover.EdgeColor != "#2c2c2c" {
+		t.Errorf("Expected no color and a dark edge color, got %+v", cover)
+	}
+	if c.Cover != cover {
+		t.Error("Expected the cover to be stored on the card")
+	}
+	if c.Name != "Learn about the Trello API" {
+		t.Errorf("Expected the other fields of the card to be kept, got name '%s'", c.Name)
+	}
+}
+
+func TestCardEmptyCover(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-empty-cover.json")
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	card, err := c.GetCard("4eea503d91e31d174600008f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Cover == nil || *card.Cover != (Cover{}) {
+		t.Errorf("Expected a zero cover, got %+v", card.Cover)
+	}
+}
+
+func TestCardSetDueReminder(t *testing.T) {
+	c := testCard(t)
+	due := time.Date(2020, 7, 27, 17, 0, 0, 0, time.UTC)
+	c.Due = &due
+
+	server := NewMockResponder(t, "cards", "card-due-reminder-set.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Query().Get("dueReminder") != "60" {
+			t.Errorf("Expected a PUT with dueReminder 60, got %s '%s'", r.Method, r.URL.RawQuery)
+		}
+	})
+	c.client.BaseURL = server.URL()
+	err := c.SetDueReminder(60)
+	server.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.DueReminder == nil || *c.DueReminder != 60 {
+		t.Errorf("Expected a reminder 60 minutes before the due date, got %v", c.DueReminder)
+	}
+
+	server = NewMockResponder(t, "cards", "card-due-reminder-cleared.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Query().Get("dueReminder") != "-1" {
+			t.Errorf("Expected dueReminder -1, got '%s'", r.URL.RawQuery)
+		}
+	})
+	c.client.BaseURL = server.URL()
+	err = c.SetDueReminder(-1)
+	server.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.DueReminder == nil || *c.DueReminder != -1 {
+		t.Errorf("Expected the reminder to be cleared, got %v", c.DueReminder)
+	}
+}
+
+func TestCardSetDueReminderWithoutDue(t *testing.T) {
+	c := testCard(t)
+	c.Due = nil
+	c.client.BaseURL = mockErrorResponse(http.StatusInternalServerError).URL
+
+	err := c.SetDueReminder(60)
+	if err == nil || !strings.Contains(err.Error(), "no due date") {
+		t.Errorf("Expected an error for a card without due date, got %v", err)
+	}
+	if err = c.SetDueReminder(-2); err == nil {
+		t.Error("Expected an error for an invalid reminder")
+	}
+}
+
+func TestCardCheckItemProgress(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-check-item-states.json")
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	card, err := c.GetCard("5fe30c0a7b6e2d3c4b5a0001", Arguments{"checkItemStates": "true", "checklists": "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completed := card.CompletedCheckItems()
+	if len(completed) != 3 {
+		t.Fatalf("Expected 3 completed check items across both checklists, got %d", len(completed))
+	}
+	if completed[2].IDCheckItem != "5fe30c0a7b6e2d3c4b5a0b02" {
+		t.Errorf("Expected the item of the second checklist last, got %s", completed[2].IDCheckItem)
+	}
+	if complete, total := card.CheckItemProgress(); complete != 3 || total != 5 {
+		t.Errorf("Expected 3 of 5 check items complete, got %d of %d", complete, total)
+	}
+
+	card.Checklists = nil
+	if complete, total := card.CheckItemProgress(); complete != 3 || total != 5 {
+		t.Errorf("Expected the total from the badges, got %d of %d", complete, total)
+	}
+}
+
+func TestCardCheckItemProgressFromBadges(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-badges-only.json")
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	card, err := c.GetCard("5f4d0c0a7b6e2d3c4b5a0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(card.CompletedCheckItems()) != 0 {
+		t.Error("Expected no completed check item states")
+	}
+	if complete, total := card.CheckItemProgress(); complete != 2 || total != 2 {
+		t.Errorf("Expected 2 of 2 check items complete from the badges, got %d of %d", complete, total)
+	}
+}
+
+func TestCardVoteCountFromBadges(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-badges-only.json")
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	card, err := c.GetCard("5f4d0c0a7b6e2d3c4b5a0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Any request from here on would fail
+	c.BaseURL = mockErrorResponse(http.StatusInternalServerError).URL
+	count, err := card.VoteCount()
+	if err != nil {
+		t.Fatalf("Expected no request for loaded badges, got %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 votes, got %d", count)
+	}
+}
+
+func TestCardVoteCountWithoutBadges(t *testing.T) {
+	card := &Card{ID: "4eea503d91e31d174600008f"}
+	card.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	card.client.BaseURL = server.URL()
+
+	count, err := card.VoteCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 votes, got %d", count)
+	}
+}
+
+func TestCardVoteCountBadgesPresence(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t)
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	literal := &Card{ID: "4eea503d91e31d174600008f", Badges: CardBadges{Votes: 5}}
+	literal.SetClient(c)
+	if count, err := literal.VoteCount(); err != nil || count != 5 {
+		t.Errorf("Expected the 5 votes of the badges set by hand, got %d (%v)", count, err)
+	}
+
+	decoded := Card{}
+	if err := json.Unmarshal([]byte(`{"id": "4eea503d91e31d174600008f"}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"badges"`) {
+		t.Errorf("Expected missing badges to stay missing, got %s", data)
+	}
+	roundTripped := &Card{}
+	if err := json.Unmarshal(data, roundTripped); err != nil {
+		t.Fatal(err)
+	}
+	roundTripped.SetClient(c)
+	if count, err := roundTripped.VoteCount(); err != nil || count != 2 {
+		t.Errorf("Expected the 2 votes to be fetched for a card without badges, got %d (%v)", count, err)
+	}
+
+	zero := Card{}
+	if err := json.Unmarshal([]byte(`{"id": "4eea503d91e31d174600008f", "badges": {"votes": 0}}`), &zero); err != nil {
+		t.Fatal(err)
+	}
+	data, err = json.Marshal(zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"badges"`) {
+		t.Errorf("Expected decoded badges to be kept, got %s", data)
+	}
+}
+
+func TestCardRefresh(t *testing.T) {
+	c := testCard(t)
+	ref := c
+	c.Name = "Local change"
+	c.IDList = "5fb00c0a7b6e2d3c4b5a0001"
+
+	server := NewMockResponder(t, "cards", "card-api-example.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Query().Get("members") != "true" || r.URL.Query().Get("customFieldItems") != "true" {
+			t.Errorf("Expected the expansion arguments to be forwarded, got '%s'", r.URL.RawQuery)
+		}
+	})
+	c.client.BaseURL = server.URL()
+	client := c.client
+
+	err := c.Refresh(Arguments{"members": "true", "customFieldItems": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Name != "Learn about the Trello API" {
+		t.Errorf("Expected the server's name to win, got '%s'", ref.Name)
+	}
+	if ref.IDList != "4eea4ffc91e31d174600004b" {
+		t.Errorf("Expected the server's list to win, got '%s'", ref.IDList)
+	}
+	if ref.client != client {
+		t.Error("Expected the card to keep its client")
+	}
+}
+
+func TestCopyCardToList(t *testing.T) {
+	c := testCard(t)
+
+	server := NewMockResponder(t, "cards", "card-copied.json")
+	defer server.Close()
+	c.client.BaseURL = server.URL()
+
+	newCard, err := c.CopyToList("57f03a022cd45c863ca581f1", Defaults())
+	if err != nil {
+		t.Error(err)
+	}
+
+	if newCard.ID == c.ID {
+		t.Errorf("New card should have a new ID: '%s'.", newCard.ID)
+	}
+
+	if newCard.Pos != 16384 {
+		t.Errorf("Expected new card to have correct Pos value. Got %.2f", newCard.Pos)
+	}
+}
+
+func TestCopyCardToListWithOptions(t *testing.T) {
+	c := testCard(t)
+	c.IDCheckLists = []string{"57f51bfdf89aa46423770981"}
+
+	server := NewMockResponder(t, "cards", "card-copied-with-options.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if keep := r.URL.Query().Get("keepFromSource"); keep != "checklists,due,members" {
+			t.Errorf("Expected keepFromSource 'checklists,due,members', got '%s'", keep)
+		}
+	})
+	c.client.BaseURL = server.URL()
+
+	newCard, err := c.CopyToListWithOptions("57f03a022cd45c863ca581f1", []string{"checklists", "due", "members"}, Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if newCard.ID == c.ID {
+		t.Errorf("New card should have a new ID: '%s'.", newCard.ID)
+	}
+	if len(newCard.IDCheckLists) != 1 || newCard.IDCheckLists[0] == c.IDCheckLists[0] {
+		t.Errorf("Expected the copied checklist to have a fresh ID, got %v", newCard.IDCheckLists)
+	}
+	if len(newCard.Checklists) != 1 || newCard.Checklists[0].IDCard != newCard.ID {
+		t.Errorf("Expected the copied checklist to belong to the new card, got %v", newCard.Checklists)
+	}
+}
+
+func TestCopyCardToListWithDefaultOptions(t *testing.T) {
+	c := testCard(t)
+
+	server := NewMockResponder(t, "cards", "card-copied.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if _, ok := r.URL.Query()["keepFromSource"]; ok {
+			t.Errorf("Expected no keepFromSource for the default set, got '%s'", r.URL.RawQuery)
+		}
+	})
+	c.client.BaseURL = server.URL()
+
+	_, err := c.CopyToListWithOptions("57f03a022cd45c863ca581f1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetParentCard(t *testing.T) {
+	c := testCard(t)
+
+	server := NewMockResponder(t)
+	defer server.Close()
+	c.client.BaseURL = server.URL()
+
+	parent, err := c.GetParentCard(Defaults())
+	if err != nil {
+		t.Error(err)
+	}
+	if parent == nil {
+		t.Errorf("Problem")
+	}
+}
+
+func TestGetAncestorCards(t *testing.T) {
+	c := testCard(t)
+
+	server := mockDynamicPathResponse()
+	defer server.Close()
+	c.client.BaseURL = server.URL
+
+	ancestors, err := c.GetAncestorCards(Defaults())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(ancestors) != 1 {
+		t.Errorf("Expected 1 ancestor, got %d", len(ancestors))
+	}
+}
+
+func TestAddMemberIdToCard(t *testing.T) {
+	c := testCard(t)
+	server := NewMockResponder(t, "cards", "card-add-member-response.json")
+	defer server.Close()
+
+	c.client.BaseURL = server.URL()
+	member, err := c.AddMemberID("testmemberid")
+	if err != nil {
+		t.Error(err)
+	}
+	if member[0].ID != "testmemberid" {
+		t.Errorf("Expected id testmemberid, got %v", member[0].ID)
+	}
+	if member[0].Username != "testmemberusername" {
+		t.Errorf("Expected username testmemberusername, got %v", member[0].Username)
+	}
+}
+
+func TestAddURLAttachmentToCard(t *testing.T) {
+	c := testCard(t)
+	server := NewMockResponder(t, "cards", "url-attachments.json")
+	defer server.Close()
+
+	c.client.BaseURL = server.URL()
+	attachment := Attachment{
+		Name: "Test",
+		URL:  "https://github.com/test",
+	}
+	err := c.AddURLAttachment(&attachment)
+	if err != nil {
+		t.Error(err)
+	}
+	if attachment.ID != "5bbce18fa4a337483b145a57" {
+		t.Errorf("Expected attachment to pick up an ID, got %v instead", attachment.ID)
+	}
+}
+
+func TestAddURLAttachmentAsCover(t *testing.T) {
+	c := testCard(t)
+	server := NewMockResponder(t, "cards", "url-attachments.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Query().Get("setCover") != "true" {
+			t.Errorf("Expected setCover 'true', got '%s'", r.URL.Query().Get("setCover"))
+		}
+	})
+	c.client.BaseURL = server.URL()
+
+	attachment := Attachment{Name: "Screenshot", URL: "https://example.com/screenshot.png"}
+	err := c.AddURLAttachment(&attachment, Arguments{"setCover": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddURLAttachments(t *testing.T) {
+	c := testCard(t)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if name == "broken" {
+			http.Error(rw, "invalid value for url", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("setCover") != "false" {
+			t.Errorf("Expected setCover 'false' by default, got '%s'", r.URL.Query().Get("setCover"))
+		}
+		fmt.Fprintf(rw, `{"id": "id-%s", "name": "%s", "url": "%s"}`, name, name, r.URL.Query().Get("url"))
+	}))
+	defer server.Close()
+	c.client.BaseURL = server.URL
+
+	attachments := []*Attachment{
+		{Name: "spec", URL: "https://example.com/spec"},
+		{Name: "design", URL: "https://example.com/design"},
+		{Name: "notes", URL: "https://example.com/notes"},
+	}
+	err := c.AddURLAttachments(attachments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, attachment := range attachments {
+		if attachment.ID != "id-"+attachment.Name {
+			t.Errorf("Expected attachment %s to pick up its own ID, got %s", attachment.Name, attachment.ID)
+		}
+	}
+
+	attachments = []*Attachment{
+		{Name: "first", URL: "https://example.com/first"},
+		{Name: "broken", URL: "not a url"},
+	}
+	err = c.AddURLAttachments(attachments)
+	if !IsBadRequest(errors.Cause(err)) {
+		t.Errorf("Expected a bad request error, got %v", err)
+	}
+	if attachments[0].ID != "id-first" || attachments[1].ID != "" {
+		t.Errorf("Expected only the first attachment to be added, got IDs '%s' and '%s'", attachments[0].ID, attachments[1].ID)
+	}
+}
+
+func TestCardIsMirror(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-mirror.json")
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	card, err := c.GetCard("5ff40c0a7b6e2d3c4b5a0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !card.IsMirror() {
+		t.Error("Expected the card to be a mirror")
+	}
+	if card.MirrorSourceID() != "4eea503d91e31d174600008f" {
+		t.Errorf("Expected the mirror source 4eea503d91e31d174600008f, got '%s'", card.MirrorSourceID())
+	}
+
+	source := testCard(t)
+	if source.IsMirror() || source.MirrorSourceID() != "" {
+		t.Error("Didn't expect a regular card to be a mirror")
+	}
+}
+
+func TestCardRoles(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t)
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	separator, err := c.GetCard("card-separator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !separator.IsSeparator() || separator.IsLink() || separator.IsMirror() {
+		t.Errorf("Expected only a separator, got role '%s'", separator.CardRole)
+	}
+
+	link, err := c.GetCard("card-link")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !link.IsLink() || link.IsSeparator() || link.IsMirror() {
+		t.Errorf("Expected only a link, got role '%s'", link.CardRole)
+	}
+
+	card := testCard(t)
+	if card.CardRole != "" || card.IsSeparator() || card.IsLink() {
+		t.Errorf("Expected a card without role, got '%s'", card.CardRole)
+	}
+}
+
+func TestListAddMirrorCard(t *testing.T) {
+	list := testList(t)
+	server := NewMockResponder(t, "cards", "card-mirror.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		query := r.URL.Query()
+		if r.Method != http.MethodPost || r.URL.Path != "/cards" {
+			t.Errorf("Expected POST /cards, got %s %s", r.Method, r.URL.Path)
+		}
+		if query.Get("cardRole") != "mirror" || query.Get("idList") != list.ID {
+			t.Errorf("Expected a mirror card in list %s, got '%s'", list.ID, r.URL.RawQuery)
+		}
+		if query.Get("mirrorSourceId") != "4eea503d91e31d174600008f" {
+			t.Errorf("Expected mirrorSourceId to be sent, got '%s'", r.URL.RawQuery)
+		}
+	})
+	list.client.BaseURL = server.URL()
+
+	card, err := list.AddMirrorCard("4eea503d91e31d174600008f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !card.IsMirror() || card.client == nil {
+		t.Error("Expected a mirror card with a client")
+	}
+}
+
+func TestListAddMirrorCardWithSourceField(t *testing.T) {
+	list := testList(t)
+	server := NewMockResponder(t, "cards", "card-mirror.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("idCardSource") != "4eea503d91e31d174600008f" {
+			t.Errorf("Expected the source as idCardSource, got '%s'", r.URL.RawQuery)
+		}
+		if query.Get("mirrorSourceId") != "" || query.Get("sourceField") != "" {
+			t.Errorf("Didn't expect mirrorSourceId or sourceField to be sent, got '%s'", r.URL.RawQuery)
+		}
+	})
+	list.client.BaseURL = server.URL()
+
+	_, err := list.AddMirrorCard("4eea503d91e31d174600008f", Arguments{"sourceField": "idCardSource"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseCardShortLink(t *testing.T) {
+	valid := []string{
+		"https://trello.com/c/GRsvY3vZ",
+		"https://trello.com/c/GRsvY3vZ/",
+		"https://trello.com/c/GRsvY3vZ/4-learn-about-the-trello-api",
+		"https://trello.com/c/GRsvY3vZ/4-learn-about-the-trello-api?menu=filter&filter=due:week",
+		"https://trello.com/c/GRsvY3vZ#comment-5bbce18fa4a337483b145a57",
+		"http://trello.com/c/GRsvY3vZ/4-learn-about-the-trello-api",
+		"  trello.com/c/GRsvY3vZ  ",
+	}
+	for _, cardURL := range valid {
+		shortLink, err := ParseCardShortLink(cardURL)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", cardURL, err)
+			continue
+		}
+		if shortLink != "GRsvY3vZ" {
+			t.Errorf("Expected short link GRsvY3vZ for '%s', got '%s'", cardURL, shortLink)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"https://trello.com/b/nC8QJJoZ/trello-development",
+		"https://trello.com/c/",
+		"https://trello.com/c/GRsv%20Y3vZ",
+		"://trello.com/c/GRsvY3vZ",
+	}
+	for _, cardURL := range invalid {
+		if shortLink, err := ParseCardShortLink(cardURL); err == nil {
+			t.Errorf("Expected an error for '%s', got short link '%s'", cardURL, shortLink)
+		}
+	}
+}
+
+func TestGetCardByURL(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-api-example.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Path != "/cards/GRsvY3vZ" {
+			t.Errorf("Expected the card to be requested by its short link, got %s", r.URL.Path)
+		}
+	})
+	c.BaseURL = server.URL()
+
+	card, err := c.GetCardByURL("https://trello.com/c/GRsvY3vZ/4-learn-about-the-trello-api?menu=filter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.ID != "4eea503d91e31d174600008f" || card.client == nil {
+		t.Errorf("Expected card 4eea503d91e31d174600008f with a client, got %s", card.ID)
+	}
+}
+
+func TestCardAddAndRemoveLabelID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/cards/4eea503d91e31d174600008f/idLabels":
+			fmt.Fprintf(rw, `["5c41027c6a5b8e6d1f0b0a01", "%s"]`, r.URL.Query().Get("value"))
+		case r.Method == http.MethodGet && r.URL.Path == "/labels/5c41027c6a5b8e6d1f0b0a02":
+			rw.Write([]byte(`{"id": "5c41027c6a5b8e6d1f0b0a02", "idBoard": "4ed7e27fe6abb2517a21383d", "name": "Feature", "color": "green"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/cards/4eea503d91e31d174600008f/idLabels/5c41027c6a5b8e6d1f0b0a01":
+			rw.Write([]byte(`{"_value": null}`))
+		default:
+			http.Error(rw, "That label isn't on the card", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	card := &Card{
+		ID:       "4eea503d91e31d174600008f",
+		IDLabels: []string{"5c41027c6a5b8e6d1f0b0a01"},
+		Labels:   []*Label{{ID: "5c41027c6a5b8e6d1f0b0a01", Name: "Bug"}},
+	}
+	card.SetClient(testClient())
+	card.client.BaseURL = server.URL
+
+	err := card.AddLabelID("5c41027c6a5b8e6d1f0b0a02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(card.IDLabels) != 2 || card.IDLabels[1] != "5c41027c6a5b8e6d1f0b0a02" {
+		t.Errorf("Expected the label to be added to IDLabels, got %v", card.IDLabels)
+	}
+	if len(card.Labels) != 2 || card.Labels[1].Name != "Feature" || card.Labels[1].Color != "green" {
+		t.Errorf("Expected the label to be added to Labels, got %v", card.Labels)
+	}
+
+	err = card.RemoveLabelID("5c41027c6a5b8e6d1f0b0a01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(card.IDLabels) != 1 || card.IDLabels[0] != "5c41027c6a5b8e6d1f0b0a02" {
+		t.Errorf("Expected the label to be removed from IDLabels, got %v", card.IDLabels)
+	}
+	if len(card.Labels) != 1 || card.Labels[0].ID != "5c41027c6a5b8e6d1f0b0a02" {
+		t.Errorf("Expected the label to be removed from Labels, got %v", card.Labels)
+	}
+
+	err = card.RemoveLabelID("5c41027c6a5b8e6d1f0b0a09")
+	if !IsBadRequest(err) {
+		t.Errorf("Expected a bad request error for a label which isn't on the card, got %v", err)
+	}
+	if len(card.IDLabels) != 1 {
+		t.Errorf("Expected IDLabels to be left alone, got %v", card.IDLabels)
+	}
+}
+
+func TestCardAddLabelIDAlreadyLoaded(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPost {
+			t.Errorf("Didn't expect the label to be fetched, got %s %s", r.Method, r.URL.Path)
+		}
+		rw.Write([]byte(`["5c41027c6a5b8e6d1f0b0a01"]`))
+	}))
+	defer server.Close()
+
+	card := &Card{
+		ID:     "4eea503d91e31d174600008f",
+		Labels: []*Label{{ID: "5c41027c6a5b8e6d1f0b0a01", Name: "Bug"}},
+	}
+	card.SetClient(testClient())
+	card.client.BaseURL = server.URL
+
+	if err := card.AddLabelID("5c41027c6a5b8e6d1f0b0a01"); err != nil {
+		t.Fatal(err)
+	}
+	if len(card.Labels) != 1 || requests != 1 {
+		t.Errorf("Expected Labels to be left alone after a single request, got %v after %d requests", card.Labels, requests)
+	}
+}
+
+func TestCardSetClient(t *testing.T) {
+	card := Card{}
+	client := testClient()
+	card.SetClient(client)
+	if card.client == nil {
+		t.Error("Expected non-nil card.client")
+	}
+}
+
+func TestCardSetName(t *testing.T) {
+	card := &Card{
+		ID:        "5ff40c0a7b6e2d3c4b5a0011",
+		Name:      "Ship release",
+		ShortLink: "GRsvY3vZ",
+		ShortURL:  "https://trello.com/c/GRsvY3vZ",
+		URL:       "https://trello.com/c/GRsvY3vZ/12-ship-release",
+	}
+	card.SetClient(testClient())
+	server := NewMockResponder(t, "cards", "card-renamed.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/cards/5ff40c0a7b6e2d3c4b5a0011" {
+			t.Errorf("Expected PUT on the card, got %s %s", r.Method, r.URL.Path)
+		}
+		if name := r.URL.Query().Get("name"); name != "Ship the release" {
+			t.Errorf("Expected the new name to be sent, got '%s'", name)
+		}
+	})
+	card.client.BaseURL = server.URL()
+
+	err := card.SetName("Ship the release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Ship the release" {
+		t.Errorf("Expected the new name, got '%s'", card.Name)
+	}
+	if card.URL != "https://trello.com/c/GRsvY3vZ/12-ship-the-release" {
+		t.Errorf("Expected the URL slug of the new name, got '%s'", card.URL)
+	}
+	if card.ShortURL != "https://trello.com/c/GRsvY3vZ" || card.ShortLink != "GRsvY3vZ" {
+		t.Errorf("Unexpected short URL '%s' or short link '%s'", card.ShortURL, card.ShortLink)
+	}
+}
+
+func TestGetCardsByIDs(t *testing.T) {
+	c := testClient()
+	c.BatchConcurrency = 2
+
+	var mu sync.Mutex
+	inFlight, maxInFlight, batches := 0, 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		batches++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		if r.URL.Path != "/batch" {
+			t.Errorf("Expected a request to /batch, got %s", r.URL.Path)
+		}
+		routes := strings.Split(r.URL.Query().Get("urls"), ",")
+		if len(routes) > 10 {
+			t.Errorf("Expected at most 10 routes per batch, got %d", len(routes))
+		}
+		responses := []string{}
+		for _, route := range routes {
+			u, err := url.Parse(route)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fields := u.Query().Get("fields"); fields != "name,idList" {
+				t.Errorf("Expected fields 'name,idList' on route %s, got '%s'", route, fields)
+			}
+			id := strings.TrimPrefix(u.Path, "/cards/")
+			if strings.HasPrefix(id, "missing") {
+				responses = append(responses, `{"name":"NotFound","message":"The requested resource was not found.","statusCode":404}`)
+				continue
+			}
+			responses = append(responses, fmt.Sprintf(`{"200":{"id":"%s","name":"Card %s","idList":"4eea4ffc91e31d174600004a"}}`, id, id))
+		}
+		fmt.Fprintf(rw, "[%s]", strings.Join(responses, ","))
+	}))
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	ids := []string{}
+	for i := 0; i < 25; i++ {
+		if i == 3 || i == 17 {
+			ids = append(ids, fmt.Sprintf("missing%02d", i))
+			continue
+		}
+		ids = append(ids, fmt.Sprintf("5f6a0c0a7b6e2d3c4b5a00%02d", i))
+	}
+
+	cards, err := c.GetCardsByIDs(ids, Arguments{"fields": "name,idList"})
+	if !IsNotFound(err) {
+		t.Fatalf("Expected a not-found error for the missing cards, got %v", err)
+	}
+	missing := err.(*CardsNotFoundError).IDs
+	if len(missing) != 2 || missing[0] != "missing03" || missing[1] != "missing17" {
+		t.Errorf("Expected missing03 and missing17 to be reported, got %v", missing)
+	}
+
+	if len(cards) != 23 {
+		t.Fatalf("Expected 23 cards, got %d", len(cards))
+	}
+	expected := []string{}
+	for _, id := range ids {
+		if !strings.HasPrefix(id, "missing") {
+			expected = append(expected, id)
+		}
+	}
+	for i, card := range cards {
+		if card.ID != expected[i] {
+			t.Errorf("Expected card %s at position %d, got %s", expected[i], i, card.ID)
+		}
+		if card.client != c {
+			t.Errorf("Expected card %s to have its client set", card.ID)
+		}
+	}
+
+	if batches != 3 {
+		t.Errorf("Expected 3 batch requests, got %d", batches)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 batch requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestCardSetTemplate(t *testing.T) {
+	c := testCard(t)
+
+	server := NewMockResponder(t, "cards", "card-template.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/cards/"+c.ID {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if isTemplate := r.URL.Query().Get("isTemplate"); isTemplate != "true" {
+			t.Errorf("Expected isTemplate 'true', got '%s'", isTemplate)
+		}
+	})
+	c.client.BaseURL = server.URL()
+
+	err := c.SetTemplate(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsTemplate {
+		t.Error("Expected the card to be a template")
+	}
+}
+
+func TestListAddCardFromTemplate(t *testing.T) {
+	list := testList(t)
+
+	server := NewMockResponder(t, "cards", "card-from-template.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/cards" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		expected := map[string]string{
+			"idList":         list.ID,
+			"idCardSource":   "4eea503d91e31d174600008f",
+			"keepFromSource": "all",
+			"name":           "Weekly report 2020-31",
+		}
+		for key, value := range expected {
+			if query.Get(key) != value {
+				t.Errorf("Expected %s '%s', got '%s'", key, value, query.Get(key))
+			}
+		}
+	})
+	list.client.BaseURL = server.URL()
+
+	card, err := list.AddCardFromTemplate("4eea503d91e31d174600008f", "Weekly report 2020-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.IDList != list.ID || card.IsTemplate {
+		t.Errorf("Expected a regular card in list %s, got list %s (template: %t)", list.ID, card.IDList, card.IsTemplate)
+	}
+	if card.client != list.client {
+		t.Error("Expected the card to carry the list's client")
+	}
+	if len(card.Checklists) != 1 || len(card.Checklists[0].CheckItems) != 1 {
+		t.Errorf("Expected the template's checklist to come through, got %v", card.Checklists)
+	}
+	if len(card.CustomFieldItems) != 1 || card.CustomFieldItems[0].IDCustomField != "5a6a23abf958725e1ac86c21" {
+		t.Errorf("Expected the template's custom field value to come through, got %v", card.CustomFieldItems)
+	}
+}
+
+func TestListAddCardIdempotent(t *testing.T) {
+	l := testList(t)
+	var mu sync.Mutex
+	var stored []*Card
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path != "/lists/"+l.ID+"/cards" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if filter := r.URL.Query().Get("filter"); filter != "all" {
+				t.Errorf("Expected archived cards to be looked up too, got filter '%s'", filter)
+			}
+			json.NewEncoder(rw).Encode(stored)
+		case http.MethodPost:
+			posts++
+			card := &Card{
+				ID:     fmt.Sprintf("5fa0c0a7b6e2d3c4b5a0c00%d", posts),
+				Name:   r.URL.Query().Get("name"),
+				IDList: l.ID,
+			}
+			stored = append(stored, card)
+			json.NewEncoder(rw).Encode(card)
+		}
+	}))
+	defer server.Close()
+	l.client.BaseURL = server.URL
+
+	first := &Card{Name: "Invoice 1042"}
+	if err := l.AddCardIdempotent(first, "sync-1042"); err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != "5fa0c0a7b6e2d3c4b5a0c001" || first.Name != "Invoice 1042 [sync-1042]" {
+		t.Errorf("Expected the card to be created with the key in its name, got %s '%s'", first.ID, first.Name)
+	}
+
+	second := &Card{Name: "Invoice 1042"}
+	if err := l.AddCardIdempotent(second, "sync-1042"); err != nil {
+		t.Fatal(err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("Expected the existing card %s, got %s", first.ID, second.ID)
+	}
+	if second.client == nil {
+		t.Error("Expected the existing card to have a client")
+	}
+
+	other := &Card{Name: "Invoice 1043"}
+	if err := l.AddCardIdempotent(other, "sync-1043"); err != nil {
+		t.Fatal(err)
+	}
+	if other.ID == first.ID {
+		t.Error("Expected another key to create another card")
+	}
+	if posts != 2 {
+		t.Errorf("Expected 2 cards to be created, got %d", posts)
+	}
+}
+
+func TestListAddCardIdempotentConcurrent(t *testing.T) {
+	l := testList(t)
+	var mu sync.Mutex
+	var stored []*Card
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPost {
+			card := &Card{ID: fmt.Sprintf("5fa0c0a7b6e2d3c4b5a0c1%02d", len(stored)), Name: r.URL.Query().Get("name")}
+			stored = append(stored, card)
+			json.NewEncoder(rw).Encode(card)
+			return
+		}
+		json.NewEncoder(rw).Encode(stored)
+	}))
+	defer server.Close()
+	l.client.BaseURL = server.URL
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.AddCardIdempotent(&Card{Name: "Invoice 2001"}, "sync-2001"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(stored) != 1 {
+		t.Errorf("Expected a single card to be created, got %d", len(stored))
+	}
+	if n := len(l.client.idempotencyLocks.locks); n != 0 {
+		t.Errorf("Expected the lock to be dropped once done, got %d locks left", n)
+	}
+}
+
+func TestListAddCardIdempotentSeparateClients(t *testing.T) {
+	l := testList(t)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			rw.Write([]byte(`{"id": "5fa0c0a7b6e2d3c4b5a0c201"}`))
+			return
+		}
+		rw.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	l.client.BaseURL = server.URL
+
+	unlock := l.client.idempotencyLocks.lock(l.ID + "/sync-3001")
+	defer unlock()
+
+	other := *l
+	other.SetClient(testClient())
+	other.client.BaseURL = server.URL
+	done := make(chan error, 1)
+	go func() {
+		done <- other.AddCardIdempotent(&Card{Name: "Invoice 3001"}, "sync-3001")
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected another client not to wait for the lock of the first one")
+	}
+}
+
+func TestListAddCardIdempotentWithoutKey(t *testing.T) {
+	l := testList(t)
+	if err := l.AddCardIdempotent(&Card{Name: "Invoice"}, ""); err == nil {
+		t.Error("Expected an error without idempotency key")
+	}
+}
+
+func TestCardResolveLabels(t *testing.T) {
+	boardLabels := []*Label{
+		{ID: "5fb0c0a7b6e2d3c4b5a0d001", Name: "Bug", Color: "red"},
+		{ID: "5fb0c0a7b6e2d3c4b5a0d002", Name: "Feature", Color: "green"},
+		{ID: "5fb0c0a7b6e2d3c4b5a0d003", Name: "Chore", Color: "sky"},
+	}
+	card := &Card{IDLabels: []string{"5fb0c0a7b6e2d3c4b5a0d002", "5fb0c0a7b6e2d3c4b5a0dfff", "5fb0c0a7b6e2d3c4b5a0d001"}}
+
+	labels := card.ResolveLabels(boardLabels)
+	if len(labels) != 2 {
+		t.Fatalf("Expected 2 labels, got %d", len(labels))
+	}
+	if labels[0].Name != "Feature" || labels[1].Name != "Bug" {
+		t.Errorf("Expected the card's label order, got '%s' and '%s'", labels[0].Name, labels[1].Name)
+	}
+
+	if labels := (&Card{}).ResolveLabels(boardLabels); labels == nil || len(labels) != 0 {
+		t.Errorf("Expected no labels for a card without labels, got %v", labels)
+	}
+}
+
+func TestCardCopyWithComments(t *testing.T) {
+	card := testCard(t)
+	var comments []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/cards/"+card.ID+"/actions":
+			if filter := r.URL.Query().Get("filter"); filter != "commentCard" {
+				t.Errorf("Expected the comments to be requested, got filter '%s'", filter)
+			}
+			// Trello returns the newest comment first
+			rw.Write([]byte(`[
+				{"id": "5fd20c0a7b6e2d3c4b5a0102", "type": "commentCard", "date": "2020-08-22T10:30:00.000Z", "idMemberCreator": "4ee7deffe582acdec80000ac",
+				 "data": {"text": "Deployed to staging."}, "memberCreator": {"fullName": "Bob Example"}},
+				{"id": "5fd20c0a7b6e2d3c4b5a0101", "type": "commentCard", "date": "2020-08-21T14:03:00.000Z", "idMemberCreator": "4ee7df1be582acdec80000ae",
+				 "data": {"text": "Needs a review."}, "memberCreator": {"fullName": "Alice Example"}}
+			]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/cards":
+			if source := r.URL.Query().Get("idCardSource"); source != card.ID {
+				t.Errorf("Expected a copy of %s, got '%s'", card.ID, source)
+			}
+			if keep := r.URL.Query().Get("keepFromSource"); keep != "checklists" {
+				t.Errorf("Expected keepFromSource 'checklists', got '%s'", keep)
+			}
+			rw.Write([]byte(`{"id": "5fd40c0a7b6e2d3c4b5a0001", "idList": "5fd40c0a7b6e2d3c4b5a00f1", "badges": {"comments": 0}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/cards/5fd40c0a7b6e2d3c4b5a0001/actions/comments":
+			comments = append(comments, r.URL.Query().Get("text"))
+			rw.Write([]byte(`{"type": "commentCard"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	card.client.BaseURL = server.URL
+
+	newCard, err := card.CopyWithComments("5fd40c0a7b6e2d3c4b5a00f1", Arguments{"keepFromSource": "checklists"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newCard.ID != "5fd40c0a7b6e2d3c4b5a0001" {
+		t.Errorf("Expected the copy to be returned, got '%s'", newCard.ID)
+	}
+	expected := []string{
+		"Originally posted by Alice Example on 2020-08-21 14:03 UTC:\n\nNeeds a review.",
+		"Originally posted by Bob Example on 2020-08-22 10:30 UTC:\n\nDeployed to staging.",
+	}
+	if len(comments) != len(expected) {
+		t.Fatalf("Expected %d comments on the copy, got %d: %q", len(expected), len(comments), comments)
+	}
+	for i := range expected {
+		if comments[i] != expected[i] {
+			t.Errorf("Expected comment %d to be %q, got %q", i+1, expected[i], comments[i])
+		}
+	}
+}
+
+func TestCardCopyWithCommentsKeptByTrello(t *testing.T) {
+	card := testCard(t)
+	copies := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/cards" {
+			t.Errorf("Didn't expect comments to be read or replayed, got %s %s", r.Method, r.URL.Path)
+			return
+		}
+		copies++
+		// Badges missing from the response must not trigger a replay
+		rw.Write([]byte(`{"id": "5fd40c0a7b6e2d3c4b5a0001"}`))
+	}))
+	defer server.Close()
+	card.client.BaseURL = server.URL
+
+	for _, args := range []Arguments{nil, {"keepFromSource": "all"}, {"keepFromSource": "comments"}, {"keepFromSource": "attachments,comments"}} {
+		if _, err := card.CopyWithComments("5fd40c0a7b6e2d3c4b5a00f1", args); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if copies != 4 {
+		t.Errorf("Expected 4 copies, got %d", copies)
 	}
 }
 
@@ -5,7 +5,13 @@
 
 package trello
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 // Checklist represents Trello card's checklists.
 // A card can have one zero or more checklists.
@@ -96,6 +102,123 @@ func (c *Client) CreateCheckItem(checklist *Checklist, name string, extraArgs ..
 	return
 }
 
+// AddCheckItems creates a checkitem inside the receiver Checklist for every
+// name and returns them in the same order. Trello has no bulk endpoint, so the
+// checkitems are created concurrently, at most Client.BatchConcurrency at a
+// time. Unless Arguments{"pos": "..."} is given, every checkitem gets an
+// explicit position after the current last one so their order on Trello
+// matches names. If creating a checkitem fails, the checkitems created so far
+// are returned (and added to CheckItems) next to the first error.
+//
+// API Docs: https://developers.trello.com/reference#checklistsidcheckitems
+func (cl *Checklist) AddCheckItems(names []string, extraArgs ...Arguments) ([]*CheckItem, error) {
+	c := cl.client
+	path := "checklists/" + cl.ID + "/checkItems"
+
+	lastPos := 0.0
+	for _, item := range cl.CheckItems {
+		if item.Pos > lastPos {
+			lastPos = item.Pos
+		}
+	}
+
+	created := make([]*CheckItem, len(names))
+	errs := c.forEachConcurrently(len(names), func(i int) error {
+		args := Arguments{
+			"name":    names[i],
+			"pos":     strconv.FormatFloat(lastPos+float64(i+1)*c.posSpacing(), 'f', -1, 64),
+			"checked": "false",
+		}
+		args.flatten(extraArgs)
+
+		item := &CheckItem{}
+		err := c.Post(path, args, item)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to create checkitem '%s' on checklist %s", names[i], cl.ID)
+		}
+		item.SetClient(c)
+		created[i] = item
+		return nil
+	})
+
+	items := make([]*CheckItem, 0, len(names))
+	var err error
+	for i, item := range created {
+		if item == nil {
+			if err == nil {
+				err = errs[i]
+			}
+			continue
+		}
+		items = append(items, item)
+		cl.CheckItems = append(cl.CheckItems, *item)
+	}
+	return items, err
+}
+
+// RemoveCheckItem deletes the checkitem with the given id from the receiver
+// Checklist and drops it from CheckItems. Deleting an item which doesn't exist
+// returns an error for which IsNotFound() is true.
+func (cl *Checklist) RemoveCheckItem(itemID string) error {
+	path := fmt.Sprintf("checklists/%s/checkItems/%s", cl.ID, itemID)
+	err := cl.client.Delete(path, Defaults(), nil)
+	if err != nil {
+		return err
+	}
+	cl.dropCheckItem(itemID)
+	return nil
+}
+
+// RemoveCheckItem deletes the checkitem with the given id from the checklist
+// with the given id on the receiver Card, and drops it from the CheckItems of
+// that checklist if the card's Checklists are loaded. Deleting an item which
+// doesn't exist returns an error for which IsNotFound() is true.
+func (c *Card) RemoveCheckItem(checklistID, itemID string) error {
+	path := fmt.Sprintf("cards/%s/checkItem/%s", c.ID, itemID)
+	err := c.client.Delete(path, Defaults(), nil)
+	if err != nil {
+		return err
+	}
+	for _, checklist := range c.Checklists {
+		if checklist.ID == checklistID {
+			checklist.dropCheckItem(itemID)
+		}
+	}
+	return nil
+}
+
+// ConvertCheckItemToCard promotes the checkitem with the given id on the
+// checklist with the given id into a card of its own, and drops the item from
+// that checklist if the receiver's Checklists are loaded. Unless extraArgs say
+// otherwise Trello creates the new card in the same list as the receiver.
+func (c *Card) ConvertCheckItemToCard(checklistID, checkItemID string, extraArgs ...Arguments) (*Card, error) {
+	path := fmt.Sprintf("cards/%s/checklist/%s/checkItem/%s/convertToCard", c.ID, checklistID, checkItemID)
+	args := flattenArguments(extraArgs)
+	newCard := &Card{}
+	err := c.client.Post(path, args, newCard)
+	if err != nil {
+		return nil, err
+	}
+	newCard.SetClient(c.client)
+	for _, checklist := range c.Checklists {
+		if checklist.ID == checklistID {
+			checklist.dropCheckItem(checkItemID)
+		}
+	}
+	return newCard, nil
+}
+
+// dropCheckItem removes the checkitem with the given id from CheckItems.
+func (cl *Checklist) dropCheckItem(itemID string) {
+	items := cl.CheckItems[:0]
+	for _, item := range cl.CheckItems {
+		if item.ID != itemID {
+			items = append(items, item)
+		}
+	}
+	cl.CheckItems = items
+}
+
 // GetChecklist receives a checklist id and Arguments and returns the checklist if found
 // with the credentials given for the receiver Client. Returns an error
 // otherwise.
@@ -108,6 +231,27 @@ func (c *Client) GetChecklist(checklistID string, args Arguments) (checklist *Ch
 	return checklist, err
 }
 
+// GetChecklists takes Arguments and returns all checklists on the cards of
+// the receiver Board. The check items are included by default
+// (Arguments{"checkItems": "all"}), pass "none" to leave them out. Trello
+// returns all checklists of the board unless Arguments{"limit": "..."} is
+// given. The idCard field is always requested, so the checklists can be
+// related to their cards even if a restricted set of fields is asked for.
+func (b *Board) GetChecklists(extraArgs ...Arguments) (checklists []*Checklist, err error) {
+	args := Arguments{"checkItems": "all"}
+	args.flatten(extraArgs)
+	if fields, ok := args["fields"]; ok && fields != "all" && !strings.Contains(","+fields+",", ",idCard,") {
+		args["fields"] = fields + ",idCard"
+	}
+
+	path := fmt.Sprintf("boards/%s/checklists", b.ID)
+	err = b.client.Get(path, args, &checklists)
+	for _, checklist := range checklists {
+		checklist.SetClient(b.client)
+	}
+	return
+}
+
 // SetClient can be used to override this Checklist's internal connection to the
 // Trello API. Normally, this is set automatically after API calls.
 func (cl *Checklist) SetClient(newClient *Client) {
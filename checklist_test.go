@@ -1,7 +1,14 @@
 package trello
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 func TestCreateChecklist(t *testing.T) {
@@ -148,3 +155,237 @@ func testChecklist(t *testing.T) *Checklist {
 	}
 	return checklist
 }
+
+func TestGetChecklistsOnBoard(t *testing.T) {
+	board := testBoard(t)
+	server := NewMockResponder(t)
+	defer server.Close()
+	board.client.BaseURL = server.URL()
+
+	checklists, err := board.GetChecklists()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(checklists) != 3 {
+		t.Fatalf("Expected 3 checklists, got %d", len(checklists))
+	}
+	cards := map[string]bool{}
+	for _, checklist := range checklists {
+		if checklist.IDCard == "" {
+			t.Errorf("Expected checklist %s to have its IDCard set", checklist.ID)
+		}
+		if checklist.client == nil {
+			t.Errorf("Expected checklist %s to have a client", checklist.ID)
+		}
+		cards[checklist.IDCard] = true
+	}
+	if len(cards) != 2 {
+		t.Errorf("Expected checklists of 2 cards, got %d", len(cards))
+	}
+	if len(checklists[0].CheckItems) != 2 {
+		t.Errorf("Expected 2 check items on the first checklist, got %d", len(checklists[0].CheckItems))
+	}
+}
+
+func TestGetChecklistsOnBoardRequestsIDCard(t *testing.T) {
+	board := testBoard(t)
+	server := NewMockResponder(t, "boards", "4ed7e27fe6abb2517a21383d", "checklists-1328e80e783874f7b7c86acda29f110d.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("fields") != "name,idCard" {
+			t.Errorf("Expected fields 'name,idCard', got '%s'", query.Get("fields"))
+		}
+		if query.Get("limit") != "2" {
+			t.Errorf("Expected limit '2', got '%s'", query.Get("limit"))
+		}
+		if query.Get("checkItems") != "none" {
+			t.Errorf("Expected checkItems 'none', got '%s'", query.Get("checkItems"))
+		}
+	})
+	board.client.BaseURL = server.URL()
+
+	_, err := board.GetChecklists(Arguments{"fields": "name", "limit": "2", "checkItems": "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddCheckItems(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		query := r.URL.Query()
+		name := query.Get("name")
+		if name == "fail" {
+			http.Error(rw, "invalid value for name", http.StatusBadRequest)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprintf(rw, `{"id": "id-%s", "name": "%s", "state": "incomplete", "idChecklist": "5cc05fc2a44eed7872662d1b", "pos": %s}`, name, name, query.Get("pos"))
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+	cl := &Checklist{ID: "5cc05fc2a44eed7872662d1b", CheckItems: []CheckItem{{ID: "existing", Pos: 1000}}}
+	cl.SetClient(c)
+
+	names := []string{"one", "two", "three", "four", "five"}
+	items, err := cl.AddCheckItems(names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != len(names) {
+		t.Fatalf("Expected %d checkitems, got %d", len(names), len(items))
+	}
+	for i, item := range items {
+		if item.Name != names[i] {
+			t.Errorf("Expected checkitem %d to be '%s', got '%s'", i, names[i], item.Name)
+		}
+		if i > 0 && item.Pos <= items[i-1].Pos {
+			t.Errorf("Expected increasing positions, got %v after %v", item.Pos, items[i-1].Pos)
+		}
+	}
+	if items[0].Pos <= 1000 {
+		t.Errorf("Expected the checkitems after the existing one, got pos %v", items[0].Pos)
+	}
+	if len(cl.CheckItems) != 6 || cl.CheckItems[5].Name != "five" {
+		t.Errorf("Expected the checkitems to be appended to the checklist, got %d", len(cl.CheckItems))
+	}
+	if maxInFlight > c.batchConcurrency() {
+		t.Errorf("Expected at most %d requests in flight, got %d", c.batchConcurrency(), maxInFlight)
+	}
+
+	items, err = cl.AddCheckItems([]string{"six", "fail", "seven"})
+	if !IsBadRequest(errors.Cause(err)) {
+		t.Errorf("Expected a bad request error, got %v", err)
+	}
+	if len(items) != 2 || items[0].Name != "six" || items[1].Name != "seven" {
+		t.Errorf("Expected the created checkitems to be returned in order, got %d", len(items))
+	}
+	if len(cl.CheckItems) != 8 {
+		t.Errorf("Expected the created checkitems to be appended, got %d", len(cl.CheckItems))
+	}
+}
+
+func TestChecklistRemoveCheckItem(t *testing.T) {
+	checklist := &Checklist{ID: "5f3b0c0a7b6e2d3c4b5a0c01", CheckItems: []CheckItem{{ID: "5f3b0c0a7b6e2d3c4b5a0c11"}, {ID: "5f3b0c0a7b6e2d3c4b5a0c12"}}}
+	checklist.SetClient(testClient())
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/checklists/5f3b0c0a7b6e2d3c4b5a0c01/checkItems/5f3b0c0a7b6e2d3c4b5a0c11" {
+			t.Errorf("Expected DELETE on the checkitem, got %s %s", r.Method, r.URL.Path)
+		}
+		rw.Write([]byte(`{"limits": {}}`))
+	}))
+	defer server.Close()
+	checklist.client.BaseURL = server.URL
+
+	err := checklist.RemoveCheckItem("5f3b0c0a7b6e2d3c4b5a0c11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checklist.CheckItems) != 1 || checklist.CheckItems[0].ID != "5f3b0c0a7b6e2d3c4b5a0c12" {
+		t.Errorf("Expected only the other checkitem to be left, got %v", checklist.CheckItems)
+	}
+}
+
+func TestCardRemoveCheckItem(t *testing.T) {
+	card := testCard(t)
+	card.Checklists = []*Checklist{
+		{ID: "5f3b0c0a7b6e2d3c4b5a0c01", CheckItems: []CheckItem{{ID: "5f3b0c0a7b6e2d3c4b5a0c11"}}},
+		{ID: "5f3b0c0a7b6e2d3c4b5a0c02", CheckItems: []CheckItem{{ID: "5f3b0c0a7b6e2d3c4b5a0c21"}}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/cards/"+card.ID+"/checkItem/5f3b0c0a7b6e2d3c4b5a0c21" {
+			t.Errorf("Expected DELETE on the card's checkitem, got %s %s", r.Method, r.URL.Path)
+		}
+		rw.Write([]byte(`{"limits": {}}`))
+	}))
+	defer server.Close()
+	card.client.BaseURL = server.URL
+
+	err := card.RemoveCheckItem("5f3b0c0a7b6e2d3c4b5a0c02", "5f3b0c0a7b6e2d3c4b5a0c21")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(card.Checklists[1].CheckItems) != 0 {
+		t.Errorf("Expected the checkitem to be dropped, got %v", card.Checklists[1].CheckItems)
+	}
+	if len(card.Checklists[0].CheckItems) != 1 {
+		t.Error("Didn't expect the other checklist to change")
+	}
+}
+
+func TestRemoveCheckItemNotFound(t *testing.T) {
+	checklist := &Checklist{ID: "5f3b0c0a7b6e2d3c4b5a0c01", CheckItems: []CheckItem{{ID: "5f3b0c0a7b6e2d3c4b5a0c11"}}}
+	checklist.SetClient(testClient())
+	server := mockErrorResponse(http.StatusNotFound)
+	defer server.Close()
+	checklist.client.BaseURL = server.URL
+
+	err := checklist.RemoveCheckItem("5f3b0c0a7b6e2d3c4b5a0cff")
+	if !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+
+	card := testCard(t)
+	card.client.BaseURL = server.URL
+	err = card.RemoveCheckItem(checklist.ID, "5f3b0c0a7b6e2d3c4b5a0cff")
+	if !IsNotFound(err) {
+		t.Errorf("Expected a not found error from the card, got %v", err)
+	}
+	if len(checklist.CheckItems) != 1 {
+		t.Error("Expected the checkitems to be kept")
+	}
+}
+
+func TestCardConvertCheckItemToCard(t *testing.T) {
+	card := testCard(t)
+	card.Checklists = []*Checklist{
+		{ID: "5f3b0c0a7b6e2d3c4b5a0c01", CheckItems: []CheckItem{{ID: "5f3b0c0a7b6e2d3c4b5a0c11"}, {ID: "5f3b0c0a7b6e2d3c4b5a0c12"}}},
+	}
+	server := NewMockResponder(t, "cards", "card-converted-from-checkitem.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		expected := "/cards/" + card.ID + "/checklist/5f3b0c0a7b6e2d3c4b5a0c01/checkItem/5f3b0c0a7b6e2d3c4b5a0c12/convertToCard"
+		if r.Method != http.MethodPost || r.URL.Path != expected {
+			t.Errorf("Expected POST on %s, got %s %s", expected, r.Method, r.URL.Path)
+		}
+		if idList := r.URL.Query().Get("idList"); idList != "" {
+			t.Errorf("Didn't expect a list to be sent by default, got '%s'", idList)
+		}
+	})
+	card.client.BaseURL = server.URL()
+
+	newCard, err := card.ConvertCheckItemToCard("5f3b0c0a7b6e2d3c4b5a0c01", "5f3b0c0a7b6e2d3c4b5a0c12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newCard.ID != "5f70c0a7b6e2d3c4b5a0f001" {
+		t.Errorf("Expected the new card's ID, got '%s'", newCard.ID)
+	}
+	if newCard.IDList != card.IDList {
+		t.Errorf("Expected the new card in list '%s', got '%s'", card.IDList, newCard.IDList)
+	}
+	if newCard.client == nil {
+		t.Error("Expected the new card to have a client")
+	}
+	items := card.Checklists[0].CheckItems
+	if len(items) != 1 || items[0].ID != "5f3b0c0a7b6e2d3c4b5a0c11" {
+		t.Errorf("Expected only the converted checkitem to be dropped, got %v", items)
+	}
+}
@@ -14,6 +14,10 @@ import (
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -21,41 +25,147 @@ import (
 )
 
 // DefaultBaseURL is the default API base url used by Client to send requests to Trello.
-const DefaultBaseURL = "https://api.trello.com/1"
+const DefaultBaseURL = "https://api.trello.com/" + apiVersion
+
+// apiVersion is the version of the Trello API the Client talks to by default.
+const apiVersion = "1"
 
 // Client is the central object for making API calls. It wraps a http client,
 // context, logger and identity configuration (Key and Token) of the Trello member.
 type Client struct {
-	Client   *http.Client
-	Logger   logger
-	BaseURL  string
-	Key      string
-	Token    string
-	throttle *rate.Limiter
-	testMode bool
-	ctx      context.Context
+	Client  *http.Client
+	Logger  logger
+	BaseURL string
+	Key     string
+	Token   string
+
+	// APIVersion is the version of the Trello API the requests are sent to,
+	// "1" for clients created by NewClient. It replaces the version segment
+	// BaseURL ends with, e.g. the "1" of DefaultBaseURL. A BaseURL without
+	// one, like that of a mock server, is used as is, and so is any BaseURL
+	// if APIVersion is empty.
+	APIVersion string
+
+	// PosSpacing is the gap between the card positions assigned by the list
+	// position helpers (SortCards, BottomPos, NormalizePositions, ...).
+	// Zero means the default of 65536.
+	PosSpacing float64
+
+	// DryRun skips every request except GETs: they are logged with a
+	// "[trello] dry run, skipped" line, passed to OnDryRun and succeed
+	// without reaching Trello, leaving the target unchanged. Methods
+	// creating objects therefore return them without an ID. DoRaw returns
+	// an empty 204 No Content response for them.
+	DryRun bool
+
+	// OnDryRun, if set, is called with the method and URL of every request
+	// skipped by DryRun, e.g. to collect the writes a script would make.
+	OnDryRun func(method, url string)
+
+	// BatchConcurrency caps the number of batch requests GetCardsByIDs has
+	// in flight at once. Zero means the default of 2.
+	BatchConcurrency int
+
+	// DefaultArguments are sent with every request, e.g.
+	// Arguments{"customFieldItems": "true"}. The Arguments of a call take
+	// precedence over them.
+	DefaultArguments Arguments
+
+	// MaxRetries caps the number of times a request rejected with a 429 or
+	// 503 is retried. Zero means the default of 3, a negative value disables
+	// retrying. It is ignored if RetryPolicy is set. The options WithRetry and
+	// WithoutRetry set it as well.
+	MaxRetries int
+
+	// RetryPolicy decides whether a request is retried after its attempt-th
+	// try (starting at 1) failed with resp or err, and how long to wait
+	// before. It is asked after every try, including successful ones, and
+	// the response is returned as is once it says not to retry. Nil means
+	// DefaultRetryPolicy, capped by MaxRetries.
+	RetryPolicy func(resp *http.Response, err error, attempt int) (retry bool, wait time.Duration)
+
+	throttle         *rate.Limiter
+	customFields     *ttlCache
+	idempotencyLocks *keyedMutex
+	testMode         bool
+	ctx              context.Context
 }
 
 type logger interface {
 	Debugf(string, ...interface{})
 }
 
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
 // NewClient is a constructor for the Client. It takes the key and token credentials
-// of a Trello member to authenticate and authorise requests with.
-func NewClient(key, token string) *Client {
+// of a Trello member to authenticate and authorise requests with. The options are
+// applied in the order given, so a later option wins over an earlier one.
+func NewClient(key, token string, opts ...Option) *Client {
 	limit := rate.Every(time.Second / 8) // Actually 10/second, but we're extra cautious
 
-	return &Client{
-		Client:   http.DefaultClient,
-		BaseURL:  DefaultBaseURL,
-		Key:      key,
-		Token:    token,
-		throttle: rate.NewLimiter(limit, 1),
-		testMode: false,
-		ctx:      context.Background(),
+	c := &Client{
+		Client:           http.DefaultClient,
+		BaseURL:          DefaultBaseURL,
+		APIVersion:       apiVersion,
+		Key:              key,
+		Token:            token,
+		throttle:         rate.NewLimiter(limit, 1),
+		testMode:         false,
+		customFields:     &ttlCache{},
+		idempotencyLocks: &keyedMutex{locks: map[string]*keyedMutexEntry{}},
+		ctx:              context.Background(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithHTTPClient makes the Client send its requests with httpClient instead
+// of http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.Client = httpClient
+	}
+}
+
+// WithBaseURL makes the Client send its requests to baseURL instead of
+// DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithLogger makes the Client log its requests to l.
+func WithLogger(l logger) Option {
+	return func(c *Client) {
+		c.Logger = l
 	}
 }
 
+// WithRateLimit limits the Client to perInterval requests per interval, see
+// SetRateLimit.
+func WithRateLimit(perInterval int, interval time.Duration) Option {
+	return func(c *Client) {
+		c.SetRateLimit(perInterval, interval)
+	}
+}
+
+// WithRetry sets the MaxRetries of the Client, with the same meaning.
+func WithRetry(maxRetries int) Option {
+	return func(c *Client) {
+		c.MaxRetries = maxRetries
+	}
+}
+
+// WithoutRetry makes the Client return requests rejected with a 429 or 503
+// without retrying them, like a negative MaxRetries.
+func WithoutRetry() Option {
+	return WithRetry(-1)
+}
+
 // WithContext takes a context.Context, sets it as context on the client and returns
 // a Client pointer.
 func (c *Client) WithContext(ctx context.Context) *Client {
@@ -64,43 +174,105 @@ func (c *Client) WithContext(ctx context.Context) *Client {
 	return &newC
 }
 
-// Throttle starts receiving throttles from throttle channel each ticker period.
-func (c *Client) Throttle() {
-	if !c.testMode {
-		c.throttle.Wait(c.ctx)
+func (c *Client) posSpacing() float64 {
+	if c.PosSpacing > 0 {
+		return c.PosSpacing
 	}
+	return defaultPosSpacing
 }
 
-// Get takes a path, Arguments, and a target interface (e.g. Board or Card).
-// It runs a GET request on the Trello API endpoint and the path and uses the
-// Arguments as URL parameters. Then it returns either the target interface
-// updated from the response or an error.
-func (c *Client) Get(path string, args Arguments, target interface{}) error {
+func (c *Client) retryPolicy() func(*http.Response, error, int) (bool, time.Duration) {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy
+	}
+	limit := c.retryLimit()
+	return func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+		return retryPolicy(resp, err, attempt, limit)
+	}
+}
 
-	// Trello prohibits more than 10 seconds/second per token
-	c.Throttle()
+func (c *Client) retryLimit() int {
+	if c.MaxRetries < 0 {
+		return 0
+	}
+	if c.MaxRetries > 0 {
+		return c.MaxRetries
+	}
+	return maxRetries
+}
 
-	params := args.ToURLValues()
-	c.log("[trello] GET %s?%s", path, params.Encode())
+func (c *Client) batchConcurrency() int {
+	if c.BatchConcurrency > 0 {
+		return c.BatchConcurrency
+	}
+	return 2
+}
 
-	if c.Key != "" {
-		params.Set("key", c.Key)
+// forEachConcurrently calls fn for every i from 0 to n-1, at most
+// BatchConcurrency calls at a time, and returns the errors of the calls by i
+// once all of them returned.
+func (c *Client) forEachConcurrently(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, c.batchConcurrency())
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
 	}
+	wg.Wait()
+	return errs
+}
 
-	if c.Token != "" {
-		params.Set("token", c.Token)
+// Throttle starts receiving throttles from throttle channel each ticker period.
+func (c *Client) Throttle() {
+	if !c.testMode && c.throttle != nil {
+		c.throttle.Wait(c.ctx)
 	}
+}
 
-	url := fmt.Sprintf("%s/%s", c.BaseURL, path)
-	urlWithParams := fmt.Sprintf("%s?%s", url, params.Encode())
+// SetRateLimit limits the requests of the receiver Client, and of all clients
+// derived from it with WithContext, to perInterval requests per interval.
+// Requests beyond the limit block until they are allowed (or the client's
+// context is done). A perInterval or interval of zero disables throttling.
+// It is safe to call while requests are in flight. By default a client makes
+// at most 8 requests per second.
+func (c *Client) SetRateLimit(perInterval int, interval time.Duration) {
+	if c.throttle == nil {
+		c.throttle = rate.NewLimiter(rate.Inf, 1)
+	}
+	if perInterval <= 0 || interval <= 0 {
+		c.throttle.SetLimit(rate.Inf)
+		return
+	}
+	c.throttle.SetBurst(perInterval)
+	c.throttle.SetLimit(rate.Every(interval / time.Duration(perInterval)))
+}
 
-	req, err := http.NewRequest("GET", urlWithParams, nil)
-	if err != nil {
-		return errors.Wrapf(err, "Invalid GET request %s", url)
+// Ping checks the credentials of the Client with a minimal request for the
+// member they belong to. Rejected credentials return an error for which
+// IsPermissionDenied() is true. Network failures return an error for which
+// it isn't, so they can be told apart.
+func (c *Client) Ping() error {
+	var member struct {
+		ID string `json:"id"`
 	}
-	req = req.WithContext(c.ctx)
+	return c.Get("members/me", Arguments{"fields": "id"}, &member)
+}
 
-	return c.do(req, url, target)
+// Get takes a path, Arguments, and a target interface (e.g. Board or Card).
+// It runs a GET request on the Trello API endpoint and the path and uses the
+// Arguments as URL parameters. Then it returns either the target interface
+// updated from the response or an error.
+func (c *Client) Get(path string, args Arguments, target interface{}) error {
+	return c.Do(http.MethodGet, path, args, nil, target)
 }
 
 // Put takes a path, Arguments, and a target interface (e.g. Board or Card).
@@ -108,30 +280,7 @@ func (c *Client) Get(path string, args Arguments, target interface{}) error {
 // the Arguments as URL parameters. Then it returns either the target interface
 // updated from the response or an error.
 func (c *Client) Put(path string, args Arguments, target interface{}) error {
-
-	// Trello prohibits more than 10 seconds/second per token
-	c.Throttle()
-
-	params := args.ToURLValues()
-	c.log("[trello] PUT %s?%s", path, params.Encode())
-
-	if c.Key != "" {
-		params.Set("key", c.Key)
-	}
-
-	if c.Token != "" {
-		params.Set("token", c.Token)
-	}
-
-	url := fmt.Sprintf("%s/%s", c.BaseURL, path)
-	urlWithParams := fmt.Sprintf("%s?%s", url, params.Encode())
-
-	req, err := http.NewRequest("PUT", urlWithParams, nil)
-	if err != nil {
-		return errors.Wrapf(err, "Invalid PUT request %s", url)
-	}
-
-	return c.do(req, url, target)
+	return c.Do(http.MethodPut, path, args, nil, target)
 }
 
 // Post takes a path, Arguments, and a target interface (e.g. Board or Card).
@@ -139,30 +288,7 @@ func (c *Client) Put(path string, args Arguments, target interface{}) error {
 // the Arguments as URL parameters. Then it returns either the target interface
 // updated from the response or an error.
 func (c *Client) Post(path string, args Arguments, target interface{}) error {
-
-	// Trello prohibits more than 10 seconds/second per token
-	c.Throttle()
-
-	params := args.ToURLValues()
-	c.log("[trello] POST %s?%s", path, params.Encode())
-
-	if c.Key != "" {
-		params.Set("key", c.Key)
-	}
-
-	if c.Token != "" {
-		params.Set("token", c.Token)
-	}
-
-	url := fmt.Sprintf("%s/%s", c.BaseURL, path)
-	urlWithParams := fmt.Sprintf("%s?%s", url, params.Encode())
-
-	req, err := http.NewRequest("POST", urlWithParams, nil)
-	if err != nil {
-		return errors.Wrapf(err, "Invalid POST request %s", url)
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	return c.do(req, url, target)
+	return c.Do(http.MethodPost, path, args, nil, target)
 }
 
 // PostWithBody takes a path, Arguments, and a target interface (e.g. Board or Card).
@@ -171,108 +297,204 @@ func (c *Client) Post(path string, args Arguments, target interface{}) error {
 // Then it returns either the target interface
 // updated from the response or an error.
 func (c *Client) PostWithBody(path string, args Arguments, target interface{}, filename string, file io.Reader) error {
+	return c.postFile(path, args, target, filename, "", file)
+}
 
-	// Trello prohibits more than 10 seconds/second per token
-	c.Throttle()
-
+// postFile POSTs the file as multipart body. The part's Content-Type is set
+// to mimeType, or application/octet-stream if mimeType is empty.
+func (c *Client) postFile(path string, args Arguments, target interface{}, filename, mimeType string, file io.Reader) error {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("file", filename)
+	err := writeMultipartFile(writer, filename, mimeType, file)
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(part, file)
+
+	req, url, err := c.newRequest(http.MethodPost, path, args, body)
 	if err != nil {
 		return err
 	}
-	err = writer.Close()
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return c.do(req, url, target)
+}
+
+// postFileStreaming works like postFile, but reads the file while the request
+// is being sent instead of buffering it first. As the body can't be rewound,
+// the request isn't retried.
+func (c *Client) postFileStreaming(path string, args Arguments, target interface{}, filename, mimeType string, file io.Reader) error {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipartFile(writer, filename, mimeType, file))
+	}()
+
+	req, url, err := c.newRequest(http.MethodPost, path, args, pr)
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return c.do(req, url, target)
+}
 
-	params := args.ToURLValues()
-	c.log("[trello] POST %s?%s", path, params.Encode())
-
-	if c.Key != "" {
-		params.Set("key", c.Key)
+// writeMultipartFile writes the file as the "file" part of the multipart body
+// and closes the writer.
+func writeMultipartFile(writer *multipart.Writer, filename, mimeType string, file io.Reader) error {
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
 	}
 
-	if c.Token != "" {
-		params.Set("token", c.Token)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", mimeType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
 	}
-
-	url := fmt.Sprintf("%s/%s", c.BaseURL, path)
-	urlWithParams := fmt.Sprintf("%s?%s", url, params.Encode())
-
-	req, err := http.NewRequest("POST", urlWithParams, body)
+	_, err = io.Copy(part, file)
 	if err != nil {
-		return errors.Wrapf(err, "Invalid POST request %s", url)
+		return err
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	return c.do(req, url, target)
+	return writer.Close()
 }
 
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
 // Delete takes a path, Arguments, and a target interface (e.g. Board or Card).
 // It runs a DELETE request on the Trello API endpoint with the path and uses
 // the Arguments as URL parameters. Then it returns either the target interface
 // updated from the response or an error.
 func (c *Client) Delete(path string, args Arguments, target interface{}) error {
+	return c.Do(http.MethodDelete, path, args, nil, target)
+}
 
-	c.Throttle()
-
-	params := args.ToURLValues()
-	c.log("[trello] DELETE %s?%s", path, params.Encode())
+// PutJSON takes a path, Arguments, a source and a target interface. It runs
+// a PUT request on the Trello API endpoint with the path, the Arguments as URL
+// parameters and the source encoded as JSON body. Then it returns either the
+// target interface updated from the response or an error.
+func (c *Client) PutJSON(path string, args Arguments, source, target interface{}) error {
+	return c.Do(http.MethodPut, path, args, source, target)
+}
 
-	if c.Key != "" {
-		params.Set("key", c.Key)
-	}
+// PostJSON takes a path, Arguments, a source and a target interface. It runs
+// a POST request on the Trello API endpoint with the path, the Arguments as URL
+// parameters and the source encoded as JSON body. Then it returns either the
+// target interface updated from the response or an error.
+func (c *Client) PostJSON(path string, args Arguments, source, target interface{}) error {
+	return c.Do(http.MethodPost, path, args, source, target)
+}
 
-	if c.Token != "" {
-		params.Set("token", c.Token)
+// Do is the generic engine behind Get, Put, Post, Delete, PutJSON and PostJSON and can
+// be used for endpoints which aren't wrapped by this package. It runs a
+// request with the given method on the Trello API endpoint with the path and
+// uses the Arguments as URL parameters. An io.Reader body is sent as is, any
+// other non-nil body is encoded as JSON. Then it returns either the target interface updated from the response
+// or an error. A nil target skips decoding the response.
+func (c *Client) Do(method, path string, args Arguments, body, target interface{}) error {
+	req, url, err := c.newRequest(method, path, args, body)
+	if err != nil {
+		return err
 	}
+	return c.do(req, url, target)
+}
 
-	url := fmt.Sprintf("%s/%s", c.BaseURL, path)
-	urlWithParams := fmt.Sprintf("%s?%s", url, params.Encode())
-
-	req, err := http.NewRequest("DELETE", urlWithParams, nil)
+// DoRaw works like Do but returns the raw response of a successful request
+// instead of decoding it. The caller must close the response body. Requests
+// failing with a non-2xx status return the same typed errors as Do.
+func (c *Client) DoRaw(method, path string, args Arguments, body interface{}) (*http.Response, error) {
+	req, url, err := c.newRequest(method, path, args, body)
 	if err != nil {
-		return errors.Wrapf(err, "Invalid DELETE request %s", url)
+		return nil, err
 	}
-
-	return c.do(req, url, target)
+	return c.send(req, url)
 }
 
-func (c *Client) PutJSON(path string, args Arguments, source, target interface{}) error {
+// newRequest waits for the throttle and builds a request to the API endpoint
+// with the path, the Arguments and the client's credentials as URL
+// parameters. An io.Reader body is sent as is, any other non-nil body is
+// encoded as JSON. It also returns the URL without parameters for error
+// messages.
+func (c *Client) newRequest(method, path string, args Arguments, body interface{}) (*http.Request, string, error) {
+
+	// Trello prohibits more than 10 seconds/second per token
 	c.Throttle()
 
 	params := args.ToURLValues()
-	body, err := json.Marshal(source)
+	var reader io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+		c.log("[trello] %s %s?%s", method, path, params.Encode())
+		if method == http.MethodPost {
+			contentType = "application/x-www-form-urlencoded"
+		}
+	case io.Reader:
+		c.log("[trello] %s %s?%s", method, path, params.Encode())
+		reader = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "Invalid JSON data")
+		}
+		c.log("[trello] %s %s?%s %s", method, path, params.Encode(), string(data))
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
 
+	url := c.endpointURL(path)
+	req, err := http.NewRequest(method, c.buildURL(path, args), reader)
 	if err != nil {
-		return errors.Wrapf(err, "Invalid JSON data")
+		return nil, url, errors.Wrapf(err, "Invalid %s request %s", method, url)
 	}
+	req = req.WithContext(c.ctx)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, url, nil
+}
 
-	c.log("[trello] PUT-JSON %s?%s %s", path, params.Encode(), string(body))
-
+// buildURL returns the URL of the API endpoint at path, with the
+// DefaultArguments, the Arguments and the credentials of the Client as query
+// string.
+func (c *Client) buildURL(path string, args Arguments) string {
+	params := c.DefaultArguments.ToURLValues()
+	for key, value := range args {
+		params.Set(key, value)
+	}
 	if c.Key != "" {
 		params.Set("key", c.Key)
 	}
-
 	if c.Token != "" {
 		params.Set("token", c.Token)
 	}
+	return c.endpointURL(path) + "?" + params.Encode()
+}
 
-	url := fmt.Sprintf("%s/%s", c.BaseURL, path)
-	urlWithParams := fmt.Sprintf("%s?%s", url, params.Encode())
-
-	req, err := http.NewRequest(http.MethodPut, urlWithParams, bytes.NewBuffer(body))
-
-	if err != nil {
-		return errors.Wrapf(err, "Invalid PUT request %s", url)
+// endpointURL joins BaseURL and path with exactly one slash, regardless of
+// trailing or leading slashes on either, after replacing the version segment
+// BaseURL ends with by APIVersion. A path which repeats the version (e.g.
+// "/1/cards" on https://api.trello.com/1) doesn't double it.
+func (c *Client) endpointURL(path string) string {
+	base := strings.TrimRight(c.BaseURL, "/")
+	path = strings.TrimLeft(path, "/")
+	version := apiVersion
+	if c.APIVersion != "" {
+		version = strings.Trim(c.APIVersion, "/")
+		i := strings.LastIndex(base, "/")
+		if i > strings.Index(base, "://")+2 && isAPIVersion(base[i+1:]) {
+			base = base[:i] + "/" + version
+		}
+	}
+	if strings.HasSuffix(base, "/"+version) {
+		path = strings.TrimPrefix(path, version+"/")
 	}
+	return base + "/" + path
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	return c.do(req, url, target)
+// isAPIVersion tells whether the URL path segment is a Trello API version,
+// e.g. "1".
+func isAPIVersion(segment string) bool {
+	return segment != "" && strings.Trim(segment, "0123456789") == ""
 }
 
 func (c *Client) log(format string, args ...interface{}) {
@@ -282,24 +504,126 @@ func (c *Client) log(format string, args ...interface{}) {
 }
 
 func (c *Client) do(req *http.Request, url string, target interface{}) error {
-	resp, err := c.Client.Do(req)
+	resp, err := c.send(req, url)
 	if err != nil {
-		return errors.Wrapf(err, "HTTP request failure on %s", url)
+		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return makeHTTPClientError(url, resp)
-	} else if target == nil {
+	defer func() {
+		// Drain what the decoder didn't read, so the connection can be reused
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if target == nil || resp.StatusCode == http.StatusNoContent {
 		return nil
 	}
 
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return errors.Wrapf(err, "HTTP Read error on response for %s", url)
+	// Decode straight from the body instead of reading it into a separate
+	// buffer first. json.Decoder buffers the complete value as well, so this
+	// doesn't lower the peak memory of a large response. Like json.Unmarshal,
+	// it rejects anything but whitespace after the value.
+	dec := json.NewDecoder(resp.Body)
+	err = dec.Decode(target)
+	if err == nil {
+		if _, tokenErr := dec.Token(); tokenErr != io.EOF {
+			err = errors.New("unexpected data after the JSON value")
+		}
 	}
-	err = json.Unmarshal(b, target)
 	if err != nil {
-		return errors.Wrapf(err, "JSON decode failed on %s:\n%s", url, string(b))
+		return errors.Wrapf(err, "JSON decode failed on %s", url)
 	}
 	return nil
 }
+
+// send runs the request, retrying it as long as the retry policy allows, and
+// returns the response if its status is 2xx.
+func (c *Client) send(req *http.Request, url string) (*http.Response, error) {
+	if c.DryRun && req.Method != http.MethodGet {
+		c.log("[trello] dry run, skipped %s %s", req.Method, url)
+		if c.OnDryRun != nil {
+			c.OnDryRun(req.Method, url)
+		}
+		return &http.Response{
+			Status:     "204 No Content",
+			StatusCode: http.StatusNoContent,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	}
+
+	policy := c.retryPolicy()
+	resp, err := c.Client.Do(req)
+	for attempt := 1; ; attempt++ {
+		retry, wait := policy(resp, err, attempt)
+		if !retry || (req.Body != nil && req.GetBody == nil) {
+			break
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			c.log("[trello] %s %s returned %d, retrying in %s", req.Method, url, resp.StatusCode, wait)
+		} else {
+			c.log("[trello] %s %s failed with %v, retrying in %s", req.Method, url, err, wait)
+		}
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, errors.Wrapf(req.Context().Err(), "HTTP request failure on %s", url)
+		}
+
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to rewind request body for %s", url)
+			}
+		}
+		c.Throttle()
+		resp, err = c.Client.Do(req)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "HTTP request failure on %s", url)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, makeHTTPClientError(url, resp)
+	}
+	return resp, nil
+}
+
+// maxRetries is the number of times a request is retried after being
+// rejected with a 429 or 503, unless Client.MaxRetries says otherwise.
+const maxRetries = 3
+
+// DefaultRetryPolicy is the retry policy of a Client without RetryPolicy and
+// MaxRetries. It can be used to build a custom policy on top of it.
+func DefaultRetryPolicy(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	return retryPolicy(resp, err, attempt, maxRetries)
+}
+
+// retryPolicy decides whether a request is retried after its attempt-th try
+// and how long to wait before. Requests rejected with 429 Too Many Requests
+// or 503 Service Unavailable are retried up to limit times, waiting as
+// long as the Retry-After header asks for, or backing off exponentially from
+// half a second otherwise. Transport errors aren't retried.
+func retryPolicy(resp *http.Response, err error, attempt, limit int) (bool, time.Duration) {
+	if err != nil || resp == nil || attempt > limit {
+		return false, 0
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return false, 0
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return true, time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			wait := time.Until(date)
+			if wait < 0 {
+				wait = 0
+			}
+			return true, wait
+		}
+	}
+	return true, (time.Second / 2) << uint(attempt-1)
+}
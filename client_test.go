@@ -6,9 +6,23 @@
 package trello
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 func TestGetWithBadURL(t *testing.T) {
@@ -71,3 +85,823 @@ func testClient() *Client {
 	c.testMode = true
 	return c
 }
+
+func TestRetryPolicy(t *testing.T) {
+	resp := func(code int, retryAfter string) *http.Response {
+		r := &http.Response{StatusCode: code, Header: http.Header{}}
+		if retryAfter != "" {
+			r.Header.Set("Retry-After", retryAfter)
+		}
+		return r
+	}
+
+	tests := []struct {
+		resp    *http.Response
+		attempt int
+		retry   bool
+		wait    time.Duration
+	}{
+		{resp(http.StatusTooManyRequests, "2"), 1, true, 2 * time.Second},
+		{resp(http.StatusServiceUnavailable, ""), 1, true, 500 * time.Millisecond},
+		{resp(http.StatusServiceUnavailable, ""), 3, true, 2 * time.Second},
+		{resp(http.StatusTooManyRequests, ""), maxRetries + 1, false, 0},
+		{resp(http.StatusInternalServerError, ""), 1, false, 0},
+		{resp(http.StatusOK, ""), 1, false, 0},
+	}
+	for _, test := range tests {
+		retry, wait := retryPolicy(test.resp, nil, test.attempt, maxRetries)
+		if retry != test.retry || wait != test.wait {
+			t.Errorf("Expected (%t, %s) for %d on attempt %d, got (%t, %s)", test.retry, test.wait, test.resp.StatusCode, test.attempt, retry, wait)
+		}
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	if retry, wait := DefaultRetryPolicy(resp, nil, 1); !retry || wait != 500*time.Millisecond {
+		t.Errorf("Expected a retry after 500ms, got (%t, %s)", retry, wait)
+	}
+	if retry, _ := DefaultRetryPolicy(resp, nil, maxRetries+1); retry {
+		t.Errorf("Expected no retry after %d attempts", maxRetries)
+	}
+}
+
+func TestClientRetryPolicy(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		rw.Write([]byte(`{"id": "4ee7df1be582acdec80000ae"}`))
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+	var attempts []int
+	c.RetryPolicy = func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+		attempts = append(attempts, attempt)
+		return err == nil && resp.StatusCode == http.StatusInternalServerError, 0
+	}
+
+	member := Member{}
+	if err := c.Get("members/me", Defaults(), &member); err != nil {
+		t.Fatal(err)
+	}
+	if member.ID != "4ee7df1be582acdec80000ae" {
+		t.Errorf("Expected the last response to be decoded, got member '%s'", member.ID)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+	if !reflect.DeepEqual(attempts, []int{1, 2, 3}) {
+		t.Errorf("Expected the policy to see attempts 1 to 3, got %v", attempts)
+	}
+}
+
+func TestClientRetryPolicyRefusing(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		rw.Header().Set("Retry-After", "0")
+		rw.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+	calls := 0
+	c.RetryPolicy = func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+		calls++
+		return false, time.Hour
+	}
+
+	start := time.Now()
+	err := c.Get("members/me", Defaults(), &Member{})
+	if !IsRateLimit(err) {
+		t.Errorf("Expected the rate limit error to be returned, got %v", err)
+	}
+	if requests != 1 || calls != 1 {
+		t.Errorf("Expected a single request and policy call, got %d and %d", requests, calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected no wait when the policy refuses to retry, took %s", elapsed)
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		path    string
+		url     string
+	}{
+		{"https://api.trello.com/1", "cards/4eea503d91e31d174600008f", "https://api.trello.com/1/cards/4eea503d91e31d174600008f"},
+		{"https://api.trello.com/1", "/cards/4eea503d91e31d174600008f", "https://api.trello.com/1/cards/4eea503d91e31d174600008f"},
+		{"https://api.trello.com/1/", "cards/4eea503d91e31d174600008f", "https://api.trello.com/1/cards/4eea503d91e31d174600008f"},
+		{"https://api.trello.com/1/", "/cards/4eea503d91e31d174600008f", "https://api.trello.com/1/cards/4eea503d91e31d174600008f"},
+		{"https://api.trello.com/1", "/1/cards/4eea503d91e31d174600008f", "https://api.trello.com/1/cards/4eea503d91e31d174600008f"},
+		{"http://127.0.0.1:8080", "/cards/4eea503d91e31d174600008f", "http://127.0.0.1:8080/cards/4eea503d91e31d174600008f"},
+		{"http://127.0.0.1:8080/", "1/cards", "http://127.0.0.1:8080/1/cards"},
+	}
+	for _, test := range tests {
+		c := NewClient("user", "pass")
+		c.BaseURL = test.baseURL
+		expected := test.url + "?fields=name&key=user&token=pass"
+		if url := c.buildURL(test.path, Arguments{"fields": "name"}); url != expected {
+			t.Errorf("Expected %s for '%s' on '%s', got %s", expected, test.path, test.baseURL, url)
+		}
+	}
+}
+
+func TestBuildURLWithAPIVersion(t *testing.T) {
+	tests := []struct {
+		baseURL    string
+		apiVersion string
+		path       string
+		url        string
+	}{
+		{DefaultBaseURL, "1", "cards/4eea503d91e31d174600008f", "https://api.trello.com/1/cards/4eea503d91e31d174600008f"},
+		{DefaultBaseURL, "1", "/1/cards/4eea503d91e31d174600008f", "https://api.trello.com/1/cards/4eea503d91e31d174600008f"},
+		{DefaultBaseURL, "2", "cards", "https://api.trello.com/2/cards"},
+		{DefaultBaseURL + "/", "/2/", "/2/cards", "https://api.trello.com/2/cards"},
+		{"https://api.trello.com", "2", "cards", "https://api.trello.com/cards"},
+		{"http://127.0.0.1:8080", "1", "cards", "http://127.0.0.1:8080/cards"},
+		{"http://127.0.0.1:8080/1", "", "cards", "http://127.0.0.1:8080/1/cards"},
+		{"http://127.0.0.1:8080/1", "2", "cards", "http://127.0.0.1:8080/2/cards"},
+		{"http://127.0.0.1:8080/trello", "2", "cards", "http://127.0.0.1:8080/trello/cards"},
+		{"http://127.0.0.1:8080/trello/v2", "v2", "cards", "http://127.0.0.1:8080/trello/v2/cards"},
+	}
+	for _, test := range tests {
+		c := NewClient("user", "pass")
+		c.BaseURL = test.baseURL
+		c.APIVersion = test.apiVersion
+		expected := test.url + "?key=user&token=pass"
+		if url := c.buildURL(test.path, nil); url != expected {
+			t.Errorf("Expected %s for '%s' with version '%s' on '%s', got %s", expected, test.path, test.apiVersion, test.baseURL, url)
+		}
+	}
+}
+
+func TestNewClientAPIVersion(t *testing.T) {
+	c := NewClient("user", "pass")
+	if c.APIVersion != "1" {
+		t.Errorf("Expected API version '1' by default, got '%s'", c.APIVersion)
+	}
+	if url := c.buildURL("members/me", nil); url != "https://api.trello.com/1/members/me?key=user&token=pass" {
+		t.Errorf("Expected the default URL to be unchanged, got %s", url)
+	}
+
+	c = NewClient("user", "pass", WithBaseURL("http://127.0.0.1:8080"))
+	if url := c.buildURL("members/me", nil); url != "http://127.0.0.1:8080/members/me?key=user&token=pass" {
+		t.Errorf("Expected a custom base URL to be used as is, got %s", url)
+	}
+}
+
+func TestNewClientOptions(t *testing.T) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	logger := &recordingLogger{}
+	c := NewClient("user", "pass",
+		WithBaseURL("http://127.0.0.1:8080/1"),
+		WithHTTPClient(httpClient),
+		WithLogger(logger),
+		WithRateLimit(20, time.Second),
+		WithRetry(5),
+		WithBaseURL("http://127.0.0.1:9090/1"),
+	)
+	if c.Key != "user" || c.Token != "pass" {
+		t.Errorf("Expected the credentials to be kept, got '%s' and '%s'", c.Key, c.Token)
+	}
+	if c.BaseURL != "http://127.0.0.1:9090/1" {
+		t.Errorf("Expected the last base URL to win, got '%s'", c.BaseURL)
+	}
+	if c.Client != httpClient {
+		t.Error("Expected the given http client")
+	}
+	if c.Logger != logger {
+		t.Error("Expected the given logger")
+	}
+	if limit := c.throttle.Limit(); limit != rate.Every(time.Second/20) || c.throttle.Burst() != 20 {
+		t.Errorf("Expected 20 requests per second, got a limit of %v with a burst of %d", limit, c.throttle.Burst())
+	}
+	if c.retryLimit() != 5 {
+		t.Errorf("Expected 5 retries, got %d", c.retryLimit())
+	}
+}
+
+func TestNewClientWithoutOptions(t *testing.T) {
+	c := NewClient("user", "pass")
+	if c.Client != http.DefaultClient || c.BaseURL != DefaultBaseURL || c.Logger != nil {
+		t.Errorf("Expected the default http client, base URL and no logger, got %v, '%s' and %v", c.Client, c.BaseURL, c.Logger)
+	}
+	if limit := c.throttle.Limit(); limit != rate.Every(time.Second/8) || c.throttle.Burst() != 1 {
+		t.Errorf("Expected the default rate limit, got a limit of %v with a burst of %d", limit, c.throttle.Burst())
+	}
+	if c.retryLimit() != maxRetries {
+		t.Errorf("Expected %d retries by default, got %d", maxRetries, c.retryLimit())
+	}
+}
+
+func TestNewClientWithRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		rw.Header().Set("Retry-After", "0")
+		rw.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		option   Option
+		requests int
+	}{
+		{"WithRetry(0)", WithRetry(0), maxRetries + 1},
+		{"WithRetry(1)", WithRetry(1), 2},
+		{"WithRetry(4)", WithRetry(4), 5},
+		{"WithRetry(-1)", WithRetry(-1), 1},
+		{"WithoutRetry()", WithoutRetry(), 1},
+	}
+	for _, test := range tests {
+		requests = 0
+		c := NewClient("user", "pass", WithBaseURL(server.URL), test.option)
+		c.testMode = true
+		if err := c.Get("members/me", Defaults(), &Member{}); !IsRateLimit(err) {
+			t.Errorf("Expected a rate limit error, got %v", err)
+		}
+		if requests != test.requests {
+			t.Errorf("Expected %d requests with %s, got %d", test.requests, test.name, requests)
+		}
+	}
+}
+
+func TestBuildURLWithoutCredentials(t *testing.T) {
+	c := NewClient("", "")
+	if url := c.buildURL("members/me", nil); url != "https://api.trello.com/1/members/me?" {
+		t.Errorf("Expected no query parameters, got %s", url)
+	}
+}
+
+func TestClientDefaultArguments(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		rw.Write([]byte(`{"id": "4eea503d91e31d174600008f"}`))
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+	c.DefaultArguments = Arguments{"fields": "all", "customFieldItems": "true"}
+
+	if _, err := c.GetCard("4eea503d91e31d174600008f"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetCard("4eea503d91e31d174600008f", Arguments{"fields": "name"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if queries[0].Get("fields") != "all" || queries[0].Get("customFieldItems") != "true" {
+		t.Errorf("Expected the default arguments to be sent, got %v", queries[0])
+	}
+	if queries[1].Get("fields") != "name" || queries[1].Get("customFieldItems") != "true" {
+		t.Errorf("Expected the call's fields to override the default, got %v", queries[1])
+	}
+	if queries[1].Get("key") != "user" || queries[1].Get("token") != "pass" {
+		t.Errorf("Expected the credentials to be kept, got %v", queries[1])
+	}
+	if c.DefaultArguments["fields"] != "all" {
+		t.Error("Didn't expect the default arguments to be modified")
+	}
+}
+
+func TestPing(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "members", "api-example.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Path != "/members/me" || r.URL.Query().Get("fields") != "id" {
+			t.Errorf("Expected GET /members/me?fields=id, got %s", r.URL)
+		}
+	})
+	c.BaseURL = server.URL()
+
+	if err := c.Ping(); err != nil {
+		t.Errorf("Expected valid credentials, got %v", err)
+	}
+}
+
+func TestPingUnauthorized(t *testing.T) {
+	c := testClient()
+	server := mockErrorResponse(http.StatusUnauthorized)
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	err := c.Ping()
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected an auth error, got %v", err)
+	}
+}
+
+func TestPingNetworkError(t *testing.T) {
+	c := testClient()
+	server := httptest.NewServer(http.NotFoundHandler())
+	c.BaseURL = server.URL
+	server.Close()
+
+	err := c.Ping()
+	if err == nil {
+		t.Fatal("Expected an error without a server")
+	}
+	if IsPermissionDenied(err) {
+		t.Errorf("Didn't expect a network error to be an auth error: %v", err)
+	}
+}
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestDryRun(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodGet {
+			t.Errorf("Didn't expect a %s request in dry run", r.Method)
+		}
+		rw.Write([]byte(`{"id": "4eea503d91e31d174600008f", "name": "Learn about the Trello API"}`))
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+	c.DryRun = true
+	logger := &recordingLogger{}
+	c.Logger = logger
+	var skipped []string
+	c.OnDryRun = func(method, url string) {
+		skipped = append(skipped, method+" "+url)
+	}
+
+	card := Card{ID: "4eea503d91e31d174600008f", Name: "Unchanged"}
+	err := c.Put("cards/4eea503d91e31d174600008f", Arguments{"name": "Renamed"}, &card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected the PUT to be skipped, got %d requests", requests)
+	}
+	if card.Name != "Unchanged" {
+		t.Errorf("Expected the target to be left unchanged, got name '%s'", card.Name)
+	}
+	if len(logger.lines) == 0 || !strings.Contains(logger.lines[len(logger.lines)-1], "dry run, skipped PUT") {
+		t.Errorf("Expected the skipped PUT to be logged, got %v", logger.lines)
+	}
+
+	resp, err := c.DoRaw(http.MethodDelete, "cards/4eea503d91e31d174600008f", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || requests != 0 {
+		t.Errorf("Expected a synthetic 204 for the DELETE, got %d after %d requests", resp.StatusCode, requests)
+	}
+
+	list := &List{ID: "4eea4ffc91e31d174600004a"}
+	list.SetClient(c)
+	created := Card{Name: "Not created"}
+	err = list.AddCard(&created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "" || requests != 0 {
+		t.Errorf("Expected no card to be created, got ID '%s' after %d requests", created.ID, requests)
+	}
+
+	expected := []string{
+		"PUT " + server.URL + "/cards/4eea503d91e31d174600008f",
+		"DELETE " + server.URL + "/cards/4eea503d91e31d174600008f",
+		"POST " + server.URL + "/lists/4eea4ffc91e31d174600004a/cards",
+	}
+	if strings.Join(skipped, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected OnDryRun to see the skipped requests %q, got %q", expected, skipped)
+	}
+
+	err = c.Get("cards/4eea503d91e31d174600008f", nil, &card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 || card.Name != "Learn about the Trello API" {
+		t.Errorf("Expected the GET to be sent, got %d requests and name '%s'", requests, card.Name)
+	}
+}
+
+func TestSetRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	timestamps := []time.Time{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		timestamps = append(timestamps, time.Now())
+		mu.Unlock()
+		rw.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("user", "pass")
+	c.BaseURL = server.URL
+	c.SetRateLimit(5, 100*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Get("members/me", Defaults(), &Member{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(timestamps) != 20 {
+		t.Fatalf("Expected 20 requests, got %d", len(timestamps))
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+
+	// A burst of 5 plus 5 refilled tokens may pass within one interval, an
+	// 11th request must wait for the following interval.
+	for i := 0; i+10 < len(timestamps); i++ {
+		if window := timestamps[i+10].Sub(timestamps[i]); window < 100*time.Millisecond {
+			t.Errorf("Expected 11 requests to span at least 100ms, requests %d to %d took %s", i, i+10, window)
+		}
+	}
+}
+
+func TestSetRateLimitDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("user", "pass")
+	c.BaseURL = server.URL
+	c.SetRateLimit(0, 0)
+
+	start := time.Now()
+	for i := 0; i < 30; i++ {
+		if err := c.Get("members/me", Defaults(), &Member{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The default limit of 8 requests per second would take over 3 seconds
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected unthrottled requests, 30 requests took %s", elapsed)
+	}
+}
+
+func TestClientDo(t *testing.T) {
+	c := testClient()
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/members/me/enterprises"},
+		{http.MethodDelete, "/cards/4eea503d91e31d174600008f/stickers/5f8c0c0a7b6e2d3c4b5a0001"},
+	}
+	for _, test := range tests {
+		server := NewMockResponder(t, "members", "api-example.json")
+		server.AssertRequest(func(t *testing.T, r *http.Request) {
+			if r.Method != test.method || r.URL.Path != test.path {
+				t.Errorf("Expected %s %s, got %s %s", test.method, test.path, r.Method, r.URL.Path)
+			}
+			query := r.URL.Query()
+			if query.Get("key") != "user" || query.Get("token") != "pass" {
+				t.Errorf("Expected credentials to be sent, got '%s'", r.URL.RawQuery)
+			}
+			if query.Get("fields") != "id" {
+				t.Errorf("Expected fields 'id', got '%s'", query.Get("fields"))
+			}
+		})
+		c.BaseURL = server.URL()
+
+		member := Member{}
+		err := c.Do(test.method, strings.TrimPrefix(test.path, "/"), Arguments{"fields": "id"}, nil, &member)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if member.ID != "4ee7df1be582acdec80000ae" {
+			t.Errorf("Expected the response to be decoded, got member '%s'", member.ID)
+		}
+	}
+}
+
+func TestPostJSON(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "cards", "card-create.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/cards" {
+			t.Errorf("Expected POST /cards, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got '%s'", r.Header.Get("Content-Type"))
+		}
+		query := r.URL.Query()
+		if query.Get("key") != "user" || query.Get("token") != "pass" || query.Get("pos") != "top" {
+			t.Errorf("Expected credentials and arguments as URL parameters, got '%s'", r.URL.RawQuery)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"idList":"57f03a06b5ff33a63c8be316","name":"Test Card Create"}` {
+			t.Errorf("Unexpected body %s", body)
+		}
+	})
+	c.BaseURL = server.URL()
+
+	source := map[string]string{"idList": "57f03a06b5ff33a63c8be316", "name": "Test Card Create"}
+	card := Card{}
+	err := c.PostJSON("cards", Arguments{"pos": "top"}, source, &card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Test Card Create" {
+		t.Errorf("Expected the response to be decoded, got card '%s'", card.Name)
+	}
+}
+
+func TestPostJSONError(t *testing.T) {
+	c := testClient()
+	c.BaseURL = mockErrorResponse(http.StatusBadRequest).URL
+
+	err := c.PostJSON("cards", nil, map[string]string{"name": ""}, &Card{})
+	if !IsBadRequest(err) {
+		t.Errorf("Expected a bad request error, got %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	c := testClient()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodDelete || r.URL.Path != "/cards/4eea503d91e31d174600008f" {
+			t.Errorf("Expected DELETE /cards/4eea503d91e31d174600008f, got %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("key") != "user" || query.Get("token") != "pass" {
+			t.Errorf("Expected credentials to be sent, got '%s'", r.URL.RawQuery)
+		}
+		if requests == 1 {
+			rw.Header().Set("Retry-After", "0")
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.Write([]byte(`{"limits": {}, "_value": null}`))
+	}))
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	var response map[string]interface{}
+	if err := c.Delete("cards/4eea503d91e31d174600008f", Defaults(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the DELETE to be retried once, got %d requests", requests)
+	}
+	if _, ok := response["limits"]; !ok {
+		t.Errorf("Expected the response to be decoded, got %v", response)
+	}
+
+	if err := c.Delete("cards/4eea503d91e31d174600008f", Defaults(), nil); err != nil {
+		t.Errorf("Expected a nil target to be accepted, got %v", err)
+	}
+}
+
+func TestDeleteNotFound(t *testing.T) {
+	c := testClient()
+	server := mockErrorResponse(http.StatusNotFound)
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	err := c.Delete("webhooks/5f8c0c0a7b6e2d3c4b5a0fff", Defaults(), nil)
+	if !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestClientDoRaw(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "members", "api-example.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected a JSON body, got Content-Type '%s'", r.Header.Get("Content-Type"))
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"fullName":"Bob"}` {
+			t.Errorf("Unexpected body %s", body)
+		}
+	})
+	c.BaseURL = server.URL()
+
+	resp, err := c.DoRaw(http.MethodPut, "members/me", nil, map[string]string{"fullName": "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"username": "bobtester"`) {
+		t.Errorf("Expected the raw response body, got %s", body)
+	}
+}
+
+func TestClientDoRawError(t *testing.T) {
+	c := testClient()
+	c.BaseURL = mockErrorResponse(http.StatusNotFound).URL
+
+	resp, err := c.DoRaw(http.MethodGet, "cards/missing", Defaults(), nil)
+	if !IsNotFound(err) {
+		t.Errorf("Expected a not-found error, got %v", err)
+	}
+	if resp != nil {
+		t.Error("Expected no response for a failed request")
+	}
+}
+
+// largeCardsResponse returns a JSON array of n cards similar to the response
+// of boards/{id}/cards.
+func largeCardsResponse(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, `{"id":"5fb00c0a7b6e2d3c4b5%05d","name":"Card %d","desc":"%s","idBoard":"4ed7e27fe6abb2517a21383d","idList":"4eea4ffc91e31d174600004a","pos":%d,"idMembers":["4ee7df1be582acdec80000ae"],"idLabels":[],"badges":{"votes":0,"comments":%d,"attachments":0,"checkItems":4,"checkItemsChecked":%d,"description":true,"due":null}}`,
+			i, i, strings.Repeat("Lorem ipsum dolor sit amet. ", 8), (i+1)*16384, i%7, i%4)
+	}
+	buf.WriteString("]")
+	return buf.Bytes()
+}
+
+func TestGetDecodesLargeResponse(t *testing.T) {
+	data := largeCardsResponse(5000)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write(data)
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+
+	var cards []*Card
+	err := c.Get("boards/4ed7e27fe6abb2517a21383d/cards", Defaults(), &cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []*Card
+	err = json.Unmarshal(data, &expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cards, expected) {
+		t.Error("Expected the decoded cards to match json.Unmarshal")
+	}
+}
+
+func TestGetDecodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(`[{"id": "5fb00c0a7b6e2d3c4b5a0001", "name": `))
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+
+	var cards []*Card
+	err := c.Get("boards/4ed7e27fe6abb2517a21383d/cards", Defaults(), &cards)
+	if err == nil || !strings.Contains(err.Error(), "JSON decode failed") {
+		t.Errorf("Expected a decode error, got %v", err)
+	}
+}
+
+func TestGetDecodeTrailingData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(`[{"id": "5fb00c0a7b6e2d3c4b5a0001"}] [{"id": "5fb00c0a7b6e2d3c4b5a0002"}]`))
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+
+	var cards []*Card
+	err := c.Get("boards/4ed7e27fe6abb2517a21383d/cards", Defaults(), &cards)
+	if err == nil || !strings.Contains(err.Error(), "JSON decode failed") {
+		t.Errorf("Expected data after the JSON value to be rejected, got %v", err)
+	}
+}
+
+func TestGetDrainsBody(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Trailing whitespace the decoder doesn't need to read
+		rw.Write([]byte(`{"id": "4eea503d91e31d174600008f"}` + strings.Repeat(" ", 1024*1024)))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			connections++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+	c.Client = &http.Client{Transport: &http.Transport{}}
+
+	for i := 0; i < 2; i++ {
+		var card Card
+		if err := c.Get("cards/4eea503d91e31d174600008f", Defaults(), &card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 1 {
+		t.Errorf("Expected the connection to be reused, got %d connections", connections)
+	}
+}
+
+func TestGetErrorKeepsBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		http.Error(rw, "invalid value for fields", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+
+	err := c.Get("boards/4ed7e27fe6abb2517a21383d/cards", Arguments{"fields": "nope"}, &[]*Card{})
+	if !IsBadRequest(err) || !strings.Contains(err.Error(), "invalid value for fields") {
+		t.Errorf("Expected a bad-request error with the response body, got %v", err)
+	}
+}
+
+// BenchmarkDecodeLargeResponse measures decoding 5000 cards from the response
+// body as Client.Get does, next to reading the whole body before
+// unmarshalling. Both hold the complete body in memory at some point, so
+// expect comparable numbers rather than a saving.
+// Run with: go test -run XXX -bench DecodeLargeResponse
+func BenchmarkDecodeLargeResponse(b *testing.B) {
+	data := largeCardsResponse(5000)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write(data)
+	}))
+	defer server.Close()
+
+	c := testClient()
+	c.BaseURL = server.URL
+
+	b.Run("decoder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var cards []*Card
+			if err := c.Get("cards", Defaults(), &cards); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			resp, err := c.DoRaw(http.MethodGet, "cards", Defaults(), nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				b.Fatal(err)
+			}
+			var cards []*Card
+			if err := json.Unmarshal(body, &cards); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
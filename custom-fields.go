@@ -1,10 +1,13 @@
 package trello
 
 import (
+	"bytes"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -13,15 +16,55 @@ import (
 //  //  //
 
 // CustomFieldItem represents the custom field items of Trello a trello card.
+// For list type fields IDValue holds the selected option and IDValues all
+// selected options, in case the field allows more than one.
 type CustomFieldItem struct {
 	ID            string           `json:"id,omitempty"`
 	Value         CustomFieldValue `json:"value,omitempty"`
 	IDValue       string           `json:"idValue,omitempty"`
+	IDValues      []string         `json:"idValues,omitempty"`
 	IDCustomField string           `json:"idCustomField,omitempty"`
 	IDModel       string           `json:"idModel,omitempty"`
 	IDModelType   string           `json:"modelType,omitempty"`
 }
 
+// UnmarshalJSON decodes a custom field item, accepting idValue both as a
+// single option ID and as an array of option IDs. The selected options of a
+// reused item are replaced, not merged.
+func (cfi *CustomFieldItem) UnmarshalJSON(b []byte) error {
+	cfi.IDValue = ""
+	cfi.IDValues = nil
+
+	type item CustomFieldItem
+	aux := struct {
+		*item
+		IDValue json.RawMessage `json:"idValue,omitempty"`
+	}{item: (*item)(cfi)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.IDValue) > 0 && aux.IDValue[0] == '[' {
+		var ids []string
+		if err := json.Unmarshal(aux.IDValue, &ids); err != nil {
+			return err
+		}
+		cfi.IDValues = ids
+	} else if len(aux.IDValue) > 0 && string(aux.IDValue) != "null" {
+		var id string
+		if err := json.Unmarshal(aux.IDValue, &id); err != nil {
+			return err
+		}
+		if id != "" && len(cfi.IDValues) == 0 {
+			cfi.IDValues = []string{id}
+		}
+	}
+	if len(cfi.IDValues) > 0 {
+		cfi.IDValue = cfi.IDValues[0]
+	}
+	return nil
+}
+
 func (c *Client) SetCustomFieldByItem(cfi CustomFieldItem, extraArgs ...Arguments) error {
 	if cfi.IDModelType != "" && cfi.IDModelType != "card" {
 		return errors.Errorf("unsupported model type: %s", cfi.IDModelType)
@@ -46,6 +89,111 @@ func (c *Client) SetCustomField(cardID, customFieldID string, value any, extraAr
 	return c.PutJSON(path, args, cfValue, nil)
 }
 
+// SetCustomFieldChecked sets the custom field on the receiver card like
+// Client.SetCustomField, but first checks that the value fits the type of the
+// field: numbers take an int, int64 or float64, checkboxes a bool, dates a
+// time.Time, text fields a string and list fields the id of one of their
+// options. driver.Valuer values are checked by the value they return. A value
+// which doesn't fit returns a *CustomFieldTypeError without any request.
+func (c *Card) SetCustomFieldChecked(field *CustomField, value any, extraArgs ...Arguments) error {
+	resolved := value
+	for {
+		valuer, ok := resolved.(driver.Valuer)
+		if !ok {
+			break
+		}
+		var err error
+		resolved, err = valuer.Value()
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get the value for custom field %s", field.Name)
+		}
+	}
+
+	mismatch := &CustomFieldTypeError{Field: field.Name, FieldType: field.Type, Value: value}
+	switch field.Type {
+	case "number":
+		switch resolved.(type) {
+		case int, int64, float64:
+		default:
+			return mismatch
+		}
+	case "checkbox":
+		if _, ok := resolved.(bool); !ok {
+			return mismatch
+		}
+	case "date":
+		if _, ok := resolved.(time.Time); !ok {
+			return mismatch
+		}
+	case "text":
+		if _, ok := resolved.(string); !ok {
+			return mismatch
+		}
+	case "list":
+		optionID, ok := resolved.(string)
+		if !ok || !field.hasOption(optionID) {
+			return mismatch
+		}
+		return c.SetCustomFieldOptions(field.ID, []string{optionID}, extraArgs...)
+	default:
+		return errors.Errorf("Unsupported type '%s' of custom field %s", field.Type, field.Name)
+	}
+
+	err := c.client.SetCustomField(c.ID, field.ID, resolved, extraArgs...)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to set custom field %s on card %s", field.Name, c.ID)
+	}
+	return nil
+}
+
+// hasOption returns true if the custom field has an option with the given id.
+func (cf *CustomField) hasOption(optionID string) bool {
+	for _, option := range cf.Options {
+		if option.ID == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCustomFieldItems takes Arguments, GETs the custom field items of the
+// receiver card and stores them in its CustomFieldItems. It works on cards
+// which were loaded without their custom field items.
+func (c *Card) GetCustomFieldItems(extraArgs ...Arguments) (items []*CustomFieldItem, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("cards/%s/customFieldItems", c.ID)
+	err = c.client.Get(path, args, &items)
+	if err == nil {
+		c.CustomFieldItems = items
+	}
+	return
+}
+
+// SetCustomFieldOptions selects the options with the given IDs on the list
+// type custom field customFieldID of the receiver card. A single option is
+// sent as a plain idValue, several ones as an array. An empty optionIDs
+// clears the field.
+func (c *Card) SetCustomFieldOptions(customFieldID string, optionIDs []string, extraArgs ...Arguments) error {
+	path := fmt.Sprintf("cards/%s/customField/%s/item", c.ID, customFieldID)
+	args := flattenArguments(extraArgs)
+
+	var body interface{}
+	switch len(optionIDs) {
+	case 0:
+		body = map[string]string{"idValue": "", "value": ""}
+	case 1:
+		body = map[string]string{"idValue": optionIDs[0]}
+	default:
+		body = map[string][]string{"idValue": optionIDs}
+	}
+
+	err := c.client.PutJSON(path, args, body, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to set the options of custom field %s on card %s", customFieldID, c.ID)
+	}
+	return nil
+}
+
 // CustomFieldValue represents the custom field value struct
 type CustomFieldValue struct {
 	val interface{}
@@ -63,13 +211,94 @@ func NewCustomFieldValue(val interface{}) CustomFieldValue {
 	return CustomFieldValue{val: val}
 }
 
-const timeFmt = "2006-01-02T15:04:05Z"
+// timeFmt is the format custom field dates are sent to Trello in (always UTC).
+const timeFmt = "2006-01-02T15:04:05.000Z"
+
+// customFieldDateFormats are the formats custom field dates are parsed with,
+// in order. RFC3339 covers dates with or without fractional seconds.
+var customFieldDateFormats = []string{time.RFC3339Nano, time.RFC3339, "2006-01-02T15:04:05Z"}
+
+// parseCustomFieldDate parses a custom field date in any of customFieldDateFormats.
+func parseCustomFieldDate(value string) (t time.Time, err error) {
+	for _, format := range customFieldDateFormats {
+		t, err = time.Parse(format, value)
+		if err == nil {
+			return
+		}
+	}
+	return t, errors.Wrapf(err, "cannot parse custom field date %s", value)
+}
 
 // Get the custom field value getter
 func (v CustomFieldValue) Get() interface{} {
 	return v.val
 }
 
+// IsSet returns true if the value holds anything, even a zero value like 0,
+// false or a zero time. Values of cleared custom fields aren't set.
+func (v CustomFieldValue) IsSet() bool {
+	return v.val != nil
+}
+
+// Equal returns true if both values are unset, or both are set and hold the
+// same value. Numbers are compared by their value regardless of their type,
+// so int(3) equals float64(3): Trello sends numbers as text and a value
+// decoded from "3" is an int, while the float64(3) it was set from isn't.
+// Times are equal if they're the same instant, whatever their location.
+// driver.Valuer values are compared by the value they resolve to.
+func (v CustomFieldValue) Equal(other CustomFieldValue) bool {
+	a, errA := resolveCustomFieldValue(v.val)
+	b, errB := resolveCustomFieldValue(other.val)
+	if errA != nil || errB != nil {
+		return false
+	}
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if x, ok := customFieldNumber(a); ok {
+		y, ok := customFieldNumber(b)
+		return ok && x == y
+	}
+	if x, ok := a.(time.Time); ok {
+		y, ok := b.(time.Time)
+		return ok && x.Equal(y)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// resolveCustomFieldValue returns the value of a driver.Valuer, any other
+// value is returned unchanged.
+func resolveCustomFieldValue(val interface{}) (interface{}, error) {
+	for {
+		valuer, ok := val.(driver.Valuer)
+		if !ok {
+			return val, nil
+		}
+		var err error
+		val, err = valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// customFieldNumber returns the value of the numeric types a custom field
+// value can hold as float64.
+func customFieldNumber(val interface{}) (float64, bool) {
+	switch n := val.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
 // String the custom field String method
 func (v CustomFieldValue) String() string {
 	return fmt.Sprintf("%s", v.val)
@@ -88,6 +317,8 @@ switchVal:
 			return nil, err
 		}
 		goto switchVal
+	case nil:
+		return json.Marshal("")
 	case string:
 		if v == "" {
 			return json.Marshal("")
@@ -104,7 +335,7 @@ switchVal:
 		}
 		return json.Marshal(cfval{Checked: "false"})
 	case time.Time:
-		return json.Marshal(cfval{Date: v.Format(timeFmt)})
+		return json.Marshal(cfval{Date: v.UTC().Format(timeFmt)})
 	case cfval:
 		return json.Marshal(v)
 	default:
@@ -112,8 +343,14 @@ switchVal:
 	}
 }
 
-// UnmarshalJSON the custom field umarshaller
+// UnmarshalJSON the custom field umarshaller. An empty string, null or an
+// empty object, which Trello uses for cleared fields, leave the value unset.
 func (v *CustomFieldValue) UnmarshalJSON(b []byte) error {
+	v.val = nil
+	if s := string(bytes.TrimSpace(b)); s == `""` || s == "null" {
+		return nil
+	}
+
 	cfval := cfval{}
 	err := json.Unmarshal(b, &cfval)
 	if err != nil {
@@ -123,7 +360,7 @@ func (v *CustomFieldValue) UnmarshalJSON(b []byte) error {
 		v.val = cfval.Text
 	}
 	if cfval.Date != "" {
-		v.val, err = time.Parse(timeFmt, cfval.Date)
+		v.val, err = parseCustomFieldDate(cfval.Date)
 		if err != nil {
 			return err
 		}
@@ -192,3 +429,91 @@ func (b *Board) GetCustomFields(extraArgs ...Arguments) (customFields []*CustomF
 	err = b.client.Get(path, args, &customFields)
 	return
 }
+
+// GetCustomFieldsCached returns the receiver board's custom fields like
+// GetCustomFields, but only asks Trello if they weren't fetched with the same
+// Arguments within ttl. The cache lives on the Client, and is shared with the
+// clients derived from it with WithContext. It is safe to call concurrently.
+// The returned slice is shared with the cache and must not be modified.
+func (b *Board) GetCustomFieldsCached(ttl time.Duration, extraArgs ...Arguments) ([]*CustomField, error) {
+	cache := lazyTTLCache(&b.client.customFields)
+	key := b.ID + "?" + flattenArguments(extraArgs).ToURLValues().Encode()
+	customFields, err := cache.get(key, ttl, func() (interface{}, error) {
+		return b.GetCustomFields(extraArgs...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return customFields.([]*CustomField), nil
+}
+
+// InvalidateCustomFieldsCache drops the receiver board's custom fields from
+// the cache of GetCustomFieldsCached, so the next call fetches them again.
+func (b *Board) InvalidateCustomFieldsCache() {
+	lazyTTLCache(&b.client.customFields).invalidate(b.ID + "?")
+}
+
+// GetCardsWithCustomFields fetches the cards of the receiver board together
+// with their custom field items, and the board's custom field definitions.
+// Both requests are made concurrently. The definitions are returned keyed by
+// their ID, which is what CustomFieldItem.IDCustomField refers to. Arguments
+// are passed along to the cards request.
+func (b *Board) GetCardsWithCustomFields(extraArgs ...Arguments) (cards []*Card, fields map[string]*CustomField, err error) {
+	args := flattenArguments(extraArgs)
+	args["customFieldItems"] = "true"
+
+	var customFields []*CustomField
+	var fieldsErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		customFields, fieldsErr = b.GetCustomFields()
+	}()
+
+	cards, err = b.GetCards(args)
+	wg.Wait()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Failed to get the cards of board %s", b.ID)
+	}
+	if fieldsErr != nil {
+		return nil, nil, errors.Wrapf(fieldsErr, "Failed to get the custom fields of board %s", b.ID)
+	}
+
+	fields = make(map[string]*CustomField, len(customFields))
+	for _, field := range customFields {
+		fields[field.ID] = field
+	}
+	return
+}
+
+// defaultCustomFieldsPerBoard is the number of custom fields Trello allows
+// per board, used when the board doesn't report its limits.
+const defaultCustomFieldsPerBoard = 50
+
+// CanAddCustomField takes Arguments, loads the receiver board's limits and
+// custom fields and returns true if another custom field can be created.
+func (b *Board) CanAddCustomField(extraArgs ...Arguments) (bool, error) {
+	path := fmt.Sprintf("boards/%s", b.ID)
+	limits := Board{}
+	err := b.client.Get(path, Arguments{"fields": "limits"}, &limits)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to get the limits of board %s", b.ID)
+	}
+	b.Limits = limits.Limits
+
+	customFields, err := b.GetCustomFields(extraArgs...)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to get the custom fields of board %s", b.ID)
+	}
+
+	limit := b.Limits.CustomFields.PerBoard
+	if limit.Status == "disabled" {
+		return false, nil
+	}
+	max := limit.DisableAt
+	if max == 0 {
+		max = defaultCustomFieldsPerBoard
+	}
+	return len(customFields) < max, nil
+}
@@ -6,7 +6,16 @@
 package trello
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestGetCustomField(t *testing.T) {
@@ -31,6 +40,24 @@ func TestGetCustomFieldsOnBoard(t *testing.T) {
 
 }
 
+func TestCanAddCustomFieldNearCap(t *testing.T) {
+	board := testBoard(t)
+	server := NewMockResponder(t)
+	defer server.Close()
+	board.client.BaseURL = server.URL()
+
+	ok, err := board.CanAddCustomField(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("Expected no headroom for another custom field")
+	}
+	if board.Limits.CustomFields.PerBoard.DisableAt != 2 {
+		t.Errorf("Expected board to pick up its custom field limit, got %d", board.Limits.CustomFields.PerBoard.DisableAt)
+	}
+}
+
 func testBoardCustomFields(t *testing.T) []*CustomField {
 	board := testBoard(t)
 	board.client.BaseURL = mockResponse("boards", "4ed7e27fe6abb2517a21383d", "customFields.json").URL
@@ -50,3 +77,502 @@ func testCustomField(t *testing.T) *CustomField {
 	}
 	return customField
 }
+
+func TestGetCardsWithCustomFields(t *testing.T) {
+	board := testBoard(t)
+	server := NewMockResponder(t)
+	defer server.Close()
+	board.client.BaseURL = server.URL()
+
+	cards, fields, err := board.GetCardsWithCustomFields(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cards) != 1 {
+		t.Fatalf("Expected 1 card, got %d", len(cards))
+	}
+	if len(fields) != 2 {
+		t.Errorf("Expected 2 custom fields, got %d", len(fields))
+	}
+	for id, field := range fields {
+		if field.ID != id {
+			t.Errorf("Expected custom field %s to be keyed by its ID, got key %s", field.ID, id)
+		}
+	}
+	for _, item := range cards[0].CustomFieldItems {
+		if _, ok := fields[item.IDCustomField]; !ok {
+			t.Errorf("Expected the definition of custom field %s to be returned", item.IDCustomField)
+		}
+	}
+}
+
+func TestSetCustomFieldOptions(t *testing.T) {
+	tests := []struct {
+		options  []string
+		expected string
+	}{
+		{[]string{"5a6a23abf958725e1ac86c22", "5a6a23abf958725e1ac86c23"}, `{"idValue":["5a6a23abf958725e1ac86c22","5a6a23abf958725e1ac86c23"]}`},
+		{[]string{"5a6a23abf958725e1ac86c22"}, `{"idValue":"5a6a23abf958725e1ac86c22"}`},
+		{[]string{}, `{"idValue":"","value":""}`},
+	}
+
+	for _, test := range tests {
+		card := testCard(t)
+		server := NewMockResponder(t, "customFields", "api-example.json")
+		server.AssertRequest(func(t *testing.T, r *http.Request) {
+			if r.Method != http.MethodPut || r.URL.Path != "/cards/"+card.ID+"/customField/5a6a23abf958725e1ac86c21/item" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != test.expected {
+				t.Errorf("Expected body %s, got %s", test.expected, body)
+			}
+		})
+		card.client.BaseURL = server.URL()
+
+		err := card.SetCustomFieldOptions("5a6a23abf958725e1ac86c21", test.options)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCustomFieldItemIDValues(t *testing.T) {
+	var items []*CustomFieldItem
+	err := json.Unmarshal([]byte(`[
+		{"id": "5ac371de1a3ac661db5cd24b", "idValue": ["5a6a23abf958725e1ac86c22", "5a6a23abf958725e1ac86c23"], "idCustomField": "5a6a23abf958725e1ac86c21"},
+		{"id": "5b101ace5ed69243295ad468", "idValue": "5ae8b4fb79ceb77fe7852df5", "idCustomField": "53a146b81c4364c3ba4250ff"},
+		{"id": "5b101ace5ed69243295ad469", "value": {"text": "free text"}, "idCustomField": "53a146b81c4364c3ba425100"}
+	]`), &items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items[0].IDValues) != 2 || items[0].IDValues[1] != "5a6a23abf958725e1ac86c23" {
+		t.Errorf("Expected 2 option IDs, got %v", items[0].IDValues)
+	}
+	if items[0].IDValue != "5a6a23abf958725e1ac86c22" {
+		t.Errorf("Expected IDValue to hold the first option, got '%s'", items[0].IDValue)
+	}
+	if items[1].IDValue != "5ae8b4fb79ceb77fe7852df5" || len(items[1].IDValues) != 1 {
+		t.Errorf("Expected a single option, got '%s' and %v", items[1].IDValue, items[1].IDValues)
+	}
+	if items[2].IDValue != "" || items[2].Value.Get() != "free text" {
+		t.Errorf("Expected a text value without options, got '%s' and %v", items[2].IDValue, items[2].Value.Get())
+	}
+}
+
+func TestCustomFieldItemIDValuesReused(t *testing.T) {
+	var item CustomFieldItem
+	if err := json.Unmarshal([]byte(`{"idValue": ["5a6a23abf958725e1ac86c22", "5a6a23abf958725e1ac86c23"]}`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"idValue": ["5a6a23abf958725e1ac86c24"]}`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if len(item.IDValues) != 1 || item.IDValue != "5a6a23abf958725e1ac86c24" {
+		t.Errorf("Expected the options to be replaced, got '%s' and %v", item.IDValue, item.IDValues)
+	}
+
+	if err := json.Unmarshal([]byte(`{"idValue": "5ae8b4fb79ceb77fe7852df5"}`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if len(item.IDValues) != 1 || item.IDValue != "5ae8b4fb79ceb77fe7852df5" {
+		t.Errorf("Expected the single option to replace the list, got '%s' and %v", item.IDValue, item.IDValues)
+	}
+
+	if err := json.Unmarshal([]byte(`{"value": {"text": "free text"}}`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.IDValue != "" || item.IDValues != nil {
+		t.Errorf("Expected no options to be left, got '%s' and %v", item.IDValue, item.IDValues)
+	}
+}
+
+func TestSetCustomFieldRetriesRateLimit(t *testing.T) {
+	c := testClient()
+
+	requests := 0
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if requests == 1 {
+			rw.Header().Set("Retry-After", "0")
+			http.Error(rw, "API_TOKEN_LIMIT_EXCEEDED", http.StatusTooManyRequests)
+			return
+		}
+		rw.Write([]byte(`{"id":"5ac371de1a3ac661db5cd24b","value":{"text":"High"}}`))
+	}))
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	err := c.SetCustomField("4eea503d91e31d174600008f", "5a6a23abf958725e1ac86c21", "High")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("Expected the PUT to be retried once, got %d requests", requests)
+	}
+	if bodies[1] != bodies[0] || bodies[1] != `{"value":{"text":"High"}}` {
+		t.Errorf("Expected the retry to resend the same body, got %q and %q", bodies[0], bodies[1])
+	}
+}
+
+func TestSetCustomFieldGivesUpOnRateLimit(t *testing.T) {
+	c := testClient()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		rw.Header().Set("Retry-After", "0")
+		http.Error(rw, "API_TOKEN_LIMIT_EXCEEDED", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	err := c.SetCustomField("4eea503d91e31d174600008f", "5a6a23abf958725e1ac86c21", "High")
+	if !IsRateLimit(err) {
+		t.Errorf("Expected a rate-limit error, got %v", err)
+	}
+	if requests != maxRetries+1 {
+		t.Errorf("Expected %d requests, got %d", maxRetries+1, requests)
+	}
+}
+
+func TestCustomFieldValueDates(t *testing.T) {
+	tests := []struct {
+		payload  string
+		expected time.Time
+	}{
+		{`{"date": "2023-01-02T15:04:05.000Z"}`, time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{`{"date": "2023-01-02T15:04:05.123Z"}`, time.Date(2023, 1, 2, 15, 4, 5, 123000000, time.UTC)},
+		{`{"date": "2023-01-02T15:04:05Z"}`, time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{`{"date": "2023-01-02T17:04:05+02:00"}`, time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		value := CustomFieldValue{}
+		err := json.Unmarshal([]byte(test.payload), &value)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", test.payload, err)
+		}
+		date, ok := value.Get().(time.Time)
+		if !ok || !date.Equal(test.expected) {
+			t.Errorf("Expected %v from %s, got %v", test.expected, test.payload, value.Get())
+		}
+
+		b, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roundTripped := CustomFieldValue{}
+		err = json.Unmarshal(b, &roundTripped)
+		if err != nil {
+			t.Fatalf("Failed to parse serialized value %s: %v", b, err)
+		}
+		if !roundTripped.Get().(time.Time).Equal(test.expected) {
+			t.Errorf("Expected %v after a round trip, got %v", test.expected, roundTripped.Get())
+		}
+	}
+}
+
+func TestCustomFieldValueDateFormat(t *testing.T) {
+	date := time.Date(2023, 1, 2, 17, 4, 5, 0, time.FixedZone("CEST", 2*60*60))
+	b, err := json.Marshal(NewCustomFieldValue(date))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"date":"2023-01-02T15:04:05.000Z"}` {
+		t.Errorf("Expected the date in UTC with milliseconds, got %s", b)
+	}
+}
+
+func TestCardGetCustomFieldItems(t *testing.T) {
+	c := testClient()
+	card := &Card{ID: "4eea503d91e31d174600008f"}
+	card.SetClient(c)
+
+	server := NewMockResponder(t)
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	items, err := card.GetCustomFieldItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 custom field items, got %d", len(items))
+	}
+	if len(card.CustomFieldItems) != 2 || card.CustomFieldItems[0] != items[0] {
+		t.Errorf("Expected the items to be stored on the card, got %v", card.CustomFieldItems)
+	}
+	if items[0].Value.Get() != 3 {
+		t.Errorf("Expected the number value 3, got %v", items[0].Value.Get())
+	}
+	if items[1].IDValue != "5a6a23abf958725e1ac86c23" {
+		t.Errorf("Expected option 5a6a23abf958725e1ac86c23, got '%s'", items[1].IDValue)
+	}
+}
+
+func TestSetCustomFieldChecked(t *testing.T) {
+	list := &CustomField{ID: "5a6a23abf958725e1ac86c21", Name: "Priority", Type: "list", Options: []*CustomFieldOption{{ID: "5a6a23abf958725e1ac86c22"}}}
+	tests := []struct {
+		field    *CustomField
+		value    any
+		expected string
+	}{
+		{&CustomField{ID: "5a98670bd6afbd6de1c8c361", Name: "Estimate", Type: "number"}, 3, `{"value":{"number":"3"}}`},
+		{&CustomField{ID: "5a98670bd6afbd6de1c8c361", Name: "Estimate", Type: "number"}, sql.NullInt64{Int64: 5, Valid: true}, `{"value":{"number":"5"}}`},
+		{&CustomField{ID: "5a98670bd6afbd6de1c8c362", Name: "Reviewed", Type: "checkbox"}, true, `{"value":{"checked":"true"}}`},
+		{&CustomField{ID: "5a98670bd6afbd6de1c8c363", Name: "Deadline", Type: "date"}, time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC), `{"value":{"date":"2021-03-01T09:00:00.000Z"}}`},
+		{&CustomField{ID: "5a98670bd6afbd6de1c8c360", Name: "Notes", Type: "text"}, "Ship it", `{"value":{"text":"Ship it"}}`},
+		{list, "5a6a23abf958725e1ac86c22", `{"idValue":"5a6a23abf958725e1ac86c22"}`},
+	}
+
+	for _, test := range tests {
+		card := testCard(t)
+		server := NewMockResponder(t, "customFields", "api-example.json")
+		server.AssertRequest(func(t *testing.T, r *http.Request) {
+			if r.Method != http.MethodPut || r.URL.Path != "/cards/"+card.ID+"/customField/"+test.field.ID+"/item" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != test.expected {
+				t.Errorf("Expected body %s for %s, got %s", test.expected, test.field.Type, body)
+			}
+		})
+		card.client.BaseURL = server.URL()
+
+		err := card.SetCustomFieldChecked(test.field, test.value)
+		server.Close()
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.field.Type, err)
+		}
+	}
+}
+
+func TestSetCustomFieldCheckedMismatch(t *testing.T) {
+	card := testCard(t)
+	card.client.BaseURL = mockErrorResponse(http.StatusInternalServerError).URL
+
+	tests := []struct {
+		field *CustomField
+		value any
+	}{
+		{&CustomField{Name: "Estimate", Type: "number"}, "3"},
+		{&CustomField{Name: "Estimate", Type: "number"}, sql.NullInt64{}},
+		{&CustomField{Name: "Reviewed", Type: "checkbox"}, "true"},
+		{&CustomField{Name: "Deadline", Type: "date"}, "2021-03-01"},
+		{&CustomField{Name: "Notes", Type: "text"}, 42},
+		{&CustomField{Name: "Priority", Type: "list", Options: []*CustomFieldOption{{ID: "5a6a23abf958725e1ac86c22"}}}, "5a6a23abf958725e1ac86c99"},
+	}
+	for _, test := range tests {
+		err := card.SetCustomFieldChecked(test.field, test.value)
+		typeErr, ok := err.(*CustomFieldTypeError)
+		if !ok {
+			t.Errorf("Expected a CustomFieldTypeError for %T on %s, got %v", test.value, test.field.Type, err)
+			continue
+		}
+		if typeErr.FieldType != test.field.Type || !IsBadRequest(err) {
+			t.Errorf("Unexpected error %v", err)
+		}
+	}
+}
+
+func TestCustomFieldValueEqual(t *testing.T) {
+	instant := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		a, b     CustomFieldValue
+		expected bool
+	}{
+		{NewCustomFieldValue(3), NewCustomFieldValue(3), true},
+		{NewCustomFieldValue(3), NewCustomFieldValue(float64(3)), true},
+		{NewCustomFieldValue(int64(3)), NewCustomFieldValue(3.5), false},
+		{NewCustomFieldValue(sql.NullInt64{Int64: 3, Valid: true}), NewCustomFieldValue(3), true},
+		{NewCustomFieldValue(true), NewCustomFieldValue(true), true},
+		{NewCustomFieldValue(true), NewCustomFieldValue(false), false},
+		{NewCustomFieldValue("Ship it"), NewCustomFieldValue("Ship it"), true},
+		{NewCustomFieldValue("Ship it"), NewCustomFieldValue("ship it"), false},
+		{NewCustomFieldValue("3"), NewCustomFieldValue(3), false},
+		{NewCustomFieldValue(instant), NewCustomFieldValue(instant.In(time.FixedZone("CEST", 2*60*60))), true},
+		{NewCustomFieldValue(instant), NewCustomFieldValue(instant.Add(time.Millisecond)), false},
+		{CustomFieldValue{}, CustomFieldValue{}, true},
+		{CustomFieldValue{}, NewCustomFieldValue(0), false},
+		{CustomFieldValue{}, NewCustomFieldValue(false), false},
+		{CustomFieldValue{}, NewCustomFieldValue(time.Time{}), false},
+		{NewCustomFieldValue(sql.NullInt64{}), CustomFieldValue{}, true},
+	}
+
+	for i, test := range tests {
+		if test.a.Equal(test.b) != test.expected || test.b.Equal(test.a) != test.expected {
+			t.Errorf("%d: expected %#v and %#v to be equal: %t", i, test.a.Get(), test.b.Get(), test.expected)
+		}
+	}
+}
+
+func TestCustomFieldValueUnsetAndZero(t *testing.T) {
+	for _, payload := range []string{`""`, `null`, `{}`} {
+		value := NewCustomFieldValue("stale")
+		err := json.Unmarshal([]byte(payload), &value)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", payload, err)
+		}
+		if value.IsSet() {
+			t.Errorf("Expected %s to leave the value unset, got %#v", payload, value.Get())
+		}
+	}
+
+	for _, payload := range []string{`{"number": "0"}`, `{"checked": "false"}`} {
+		value := CustomFieldValue{}
+		err := json.Unmarshal([]byte(payload), &value)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", payload, err)
+		}
+		if !value.IsSet() || value.Equal(CustomFieldValue{}) {
+			t.Errorf("Expected %s to be a set zero value", payload)
+		}
+	}
+}
+
+func TestCustomFieldValueRoundTrip(t *testing.T) {
+	values := []CustomFieldValue{
+		{},
+		NewCustomFieldValue(3),
+		NewCustomFieldValue(float64(3)),
+		NewCustomFieldValue(2.5),
+		NewCustomFieldValue(0),
+		NewCustomFieldValue(false),
+		NewCustomFieldValue(true),
+		NewCustomFieldValue("Ship it"),
+		NewCustomFieldValue(time.Date(2023, 1, 2, 17, 4, 5, 0, time.FixedZone("CEST", 2*60*60))),
+	}
+
+	for _, value := range values {
+		b, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("Failed to serialize %#v: %v", value.Get(), err)
+		}
+		roundTripped := CustomFieldValue{}
+		err = json.Unmarshal(b, &roundTripped)
+		if err != nil {
+			t.Fatalf("Failed to parse serialized value %s: %v", b, err)
+		}
+		if !roundTripped.Equal(value) {
+			t.Errorf("Expected %#v after a round trip through %s, got %#v", value.Get(), b, roundTripped.Get())
+		}
+	}
+}
+
+func TestCustomFieldValueNumberFormat(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{3, `{"number":"3"}`},
+		{2.5, `{"number":"2.500000"}`},
+	}
+	for _, test := range tests {
+		b, err := json.Marshal(NewCustomFieldValue(test.value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != test.expected {
+			t.Errorf("Expected %v to be serialized as %s, got %s", test.value, test.expected, b)
+		}
+	}
+}
+
+func TestBoardGetCustomFieldsCached(t *testing.T) {
+	board := testBoard(t)
+	fixture, err := ioutil.ReadFile("testdata/boards/4ed7e27fe6abb2517a21383d/customFields.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.Write(fixture)
+	}))
+	defer server.Close()
+	board.client.BaseURL = server.URL
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := board.GetCustomFieldsCached(time.Minute); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected concurrent misses to share a single request, got %d", n)
+	}
+	atomic.StoreInt32(&requests, 0)
+
+	customFields, err := board.GetCustomFieldsCached(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customFields) != 2 {
+		t.Errorf("Expected 2 custom fields, got %d", len(customFields))
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("Expected no request within the TTL, got %d", n)
+	}
+
+	if _, err := board.GetCustomFieldsCached(time.Minute, Arguments{"fields": "name"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected other Arguments to be fetched, got %d requests", n)
+	}
+
+	board.InvalidateCustomFieldsCache()
+	if _, err := board.GetCustomFieldsCached(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("Expected invalidation to force a refetch, got %d requests", n)
+	}
+
+	if _, err := board.GetCustomFieldsCached(0); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("Expected an expired entry to be refetched, got %d requests", n)
+	}
+}
+
+func TestBoardGetCustomFieldsCachedClientLiteral(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/boards/4ed7e27fe6abb2517a21383d/customFields.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.Write(fixture)
+	}))
+	defer server.Close()
+
+	board := &Board{ID: "4ed7e27fe6abb2517a21383d"}
+	client := &Client{Client: http.DefaultClient, BaseURL: server.URL}
+	board.SetClient(client.WithContext(context.Background()))
+	for i := 0; i < 2; i++ {
+		if _, err := board.GetCustomFieldsCached(time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected a client not created by NewClient to cache, got %d requests", n)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import "fmt"
+
+// Enterprise represents a Trello Enterprise, i.e. a collection of managed
+// organizations.
+// https://developer.atlassian.com/cloud/trello/rest/api-group-enterprises/
+type Enterprise struct {
+	client      *Client
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// GetEnterprise takes an enterprise id and Arguments and returns the
+// Enterprise. Tokens of members who aren't admins of the enterprise get an
+// error satisfying IsPermissionDenied().
+func (c *Client) GetEnterprise(enterpriseID string, extraArgs ...Arguments) (enterprise *Enterprise, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("enterprises/%s", enterpriseID)
+	err = c.Get(path, args, &enterprise)
+	if enterprise != nil {
+		enterprise.SetClient(c)
+	}
+	return
+}
+
+// GetOrganizations takes Arguments and returns the organizations managed by
+// the receiver Enterprise.
+func (e *Enterprise) GetOrganizations(extraArgs ...Arguments) (organizations []*Organization, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("enterprises/%s/organizations", e.ID)
+	err = e.client.Get(path, args, &organizations)
+	for _, organization := range organizations {
+		organization.SetClient(e.client)
+	}
+	return
+}
+
+// SetClient can be used to override this Enterprise's internal connection
+// to the Trello API. Normally, this is set automatically after API calls.
+func (e *Enterprise) SetClient(newClient *Client) {
+	e.client = newClient
+}
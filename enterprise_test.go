@@ -0,0 +1,66 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetEnterprise(t *testing.T) {
+	enterprise := testEnterprise(t)
+	if enterprise.DisplayName != "Acme Corporation" {
+		t.Errorf("Expected name 'Acme Corporation'. Got '%s'.", enterprise.DisplayName)
+	}
+	if enterprise.client == nil {
+		t.Error("Expected the enterprise to have a client")
+	}
+}
+
+func TestGetEnterpriseWithoutPermission(t *testing.T) {
+	c := testClient()
+	c.BaseURL = mockErrorResponse(http.StatusUnauthorized).URL
+
+	_, err := c.GetEnterprise("5e1f0c0a7b6e2d3c4b5a0c01")
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission denied error, got %v", err)
+	}
+}
+
+func TestGetOrganizationsInEnterprise(t *testing.T) {
+	enterprise := testEnterprise(t)
+	server := NewMockResponder(t)
+	defer server.Close()
+	enterprise.client.BaseURL = server.URL()
+
+	organizations, err := enterprise.GetOrganizations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(organizations) != 2 {
+		t.Fatalf("Expected 2 organizations, got %d", len(organizations))
+	}
+	if organizations[1].DisplayName != "Acme Engineering" {
+		t.Errorf("Expected 'Acme Engineering', got '%s'", organizations[1].DisplayName)
+	}
+	for _, organization := range organizations {
+		if organization.client == nil {
+			t.Errorf("Expected organization %s to have a client", organization.ID)
+		}
+	}
+}
+
+func testEnterprise(t *testing.T) *Enterprise {
+	c := testClient()
+	server := NewMockResponder(t)
+	defer server.Close()
+	c.BaseURL = server.URL()
+	enterprise, err := c.GetEnterprise("5e1f0c0a7b6e2d3c4b5a0c01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enterprise
+}
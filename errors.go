@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 )
 
 type notFoundError interface {
@@ -18,6 +19,14 @@ type permissionDeniedError interface {
 	IsPermissionDenied() bool
 }
 
+type badRequestError interface {
+	IsBadRequest() bool
+}
+
+type conflictError interface {
+	IsConflict() bool
+}
+
 type httpClientError struct {
 	msg  string
 	code int
@@ -38,6 +47,45 @@ func (e *httpClientError) Error() string            { return e.msg }
 func (e *httpClientError) IsRateLimit() bool        { return e.code == 429 }
 func (e *httpClientError) IsNotFound() bool         { return e.code == 404 }
 func (e *httpClientError) IsPermissionDenied() bool { return e.code == 401 }
+func (e *httpClientError) IsConflict() bool         { return e.code == 409 }
+func (e *httpClientError) IsBadRequest() bool       { return e.code == 400 }
+
+// staleError is returned when a conditional update is aborted because the
+// remote object changed since the caller last saw it.
+type staleError struct {
+	msg string
+}
+
+func (e *staleError) Error() string    { return e.msg }
+func (e *staleError) IsConflict() bool { return true }
+
+// CardsNotFoundError is returned by GetCardsByIDs next to the found cards
+// when some of the requested cards don't exist. IDs lists the missing cards.
+type CardsNotFoundError struct {
+	IDs []string
+}
+
+func (e *CardsNotFoundError) Error() string {
+	return fmt.Sprintf("%d cards not found: %s", len(e.IDs), strings.Join(e.IDs, ", "))
+}
+
+// IsNotFound returns true, making the error satisfy IsNotFound().
+func (e *CardsNotFoundError) IsNotFound() bool { return true }
+
+// CustomFieldTypeError is returned by SetCustomFieldChecked when the value
+// doesn't fit the type of the custom field, before anything is sent to Trello.
+type CustomFieldTypeError struct {
+	Field     string
+	FieldType string
+	Value     interface{}
+}
+
+func (e *CustomFieldTypeError) Error() string {
+	return fmt.Sprintf("custom field %s of type %s doesn't accept the %T value %v", e.Field, e.FieldType, e.Value, e.Value)
+}
+
+// IsBadRequest returns true, making the error satisfy IsBadRequest().
+func (e *CustomFieldTypeError) IsBadRequest() bool { return true }
 
 // IsRateLimit takes an error and returns true exactly if the error is a rate-limit error.
 func IsRateLimit(err error) bool {
@@ -57,3 +105,17 @@ func IsPermissionDenied(err error) bool {
 	pd, ok := err.(permissionDeniedError)
 	return ok && pd.IsPermissionDenied()
 }
+
+// IsBadRequest takes an error and returns true exactly if the error is a
+// bad-request error, e.g. one caused by an unknown or invalid argument.
+func IsBadRequest(err error) bool {
+	br, ok := err.(badRequestError)
+	return ok && br.IsBadRequest()
+}
+
+// IsConflict takes an error and returns true exactly if the error is a
+// conflict error, e.g. one returned by a conditional update of a stale object.
+func IsConflict(err error) bool {
+	ce, ok := err.(conflictError)
+	return ok && ce.IsConflict()
+}
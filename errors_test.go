@@ -52,3 +52,33 @@ func TestPermissionDeniedError(t *testing.T) {
 		t.Errorf("Expected error message 'HTTP request failure...', got: '%s'", e.Error())
 	}
 }
+
+func TestConflictError(t *testing.T) {
+	rc := ioutil.NopCloser(&bytes.Buffer{})
+	resp := &http.Response{
+		Body:       rc,
+		StatusCode: http.StatusConflict,
+	}
+	e := makeHTTPClientError("/url/string", resp)
+	if !IsConflict(e) {
+		t.Error("Expected conflict error")
+	}
+	if IsConflict(&staleError{}) != true {
+		t.Error("Expected stale error to be a conflict error")
+	}
+}
+
+func TestBadRequestError(t *testing.T) {
+	rc := ioutil.NopCloser(&bytes.Buffer{})
+	resp := &http.Response{
+		Body:       rc,
+		StatusCode: http.StatusBadRequest,
+	}
+	e := makeHTTPClientError("/url/string", resp)
+	if !IsBadRequest(e) {
+		t.Error("Expected bad request error")
+	}
+	if IsNotFound(e) {
+		t.Error("Didn't expect a not found error")
+	}
+}
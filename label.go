@@ -5,7 +5,12 @@
 
 package trello
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 // Label represents a Trello label.
 // Labels are defined per board, and can be applied to the cards on that board.
@@ -19,6 +24,46 @@ type Label struct {
 	Uses    int    `json:"uses"`
 }
 
+// LabelColor is the color of a label. The empty LabelColorNone is a label
+// without color.
+type LabelColor string
+
+// The label colors supported by Trello.
+const (
+	LabelColorNone   LabelColor = ""
+	LabelColorGreen  LabelColor = "green"
+	LabelColorYellow LabelColor = "yellow"
+	LabelColorOrange LabelColor = "orange"
+	LabelColorRed    LabelColor = "red"
+	LabelColorPurple LabelColor = "purple"
+	LabelColorBlue   LabelColor = "blue"
+	LabelColorSky    LabelColor = "sky"
+	LabelColorLime   LabelColor = "lime"
+	LabelColorPink   LabelColor = "pink"
+	LabelColorBlack  LabelColor = "black"
+)
+
+// LabelColors lists the label colors supported by Trello, LabelColorNone aside.
+var LabelColors = []LabelColor{
+	LabelColorGreen, LabelColorYellow, LabelColorOrange, LabelColorRed, LabelColorPurple,
+	LabelColorBlue, LabelColorSky, LabelColorLime, LabelColorPink, LabelColorBlack,
+}
+
+// Valid returns true if the color is one of LabelColors, its "_light" or
+// "_dark" variant, or LabelColorNone.
+func (lc LabelColor) Valid() bool {
+	if lc == LabelColorNone {
+		return true
+	}
+	base := strings.TrimSuffix(strings.TrimSuffix(string(lc), "_light"), "_dark")
+	for _, color := range LabelColors {
+		if LabelColor(base) == color {
+			return true
+		}
+	}
+	return false
+}
+
 // GetLabel takes a label id and Arguments and returns the matching label (per Trello member)
 // or an error.
 func (c *Client) GetLabel(labelID string, extraArgs ...Arguments) (label *Label, err error) {
@@ -37,7 +82,8 @@ func (b *Board) GetLabels(extraArgs ...Arguments) (labels []*Label, err error) {
 }
 
 // CreateLabel takes a Label and Arguments and POSTs the label to the Board
-// API. Returns an error if the operation fails.
+// API. Returns an error if the operation fails, or without making a request
+// if the label's color isn't a valid LabelColor.
 func (b *Board) CreateLabel(label *Label, extraArgs ...Arguments) error {
 	path := fmt.Sprintf("boards/%s/labels/", b.ID)
 	args := Arguments{
@@ -46,6 +92,9 @@ func (b *Board) CreateLabel(label *Label, extraArgs ...Arguments) error {
 		"idBoard": b.ID,
 	}
 	args.flatten(extraArgs)
+	if !LabelColor(args["color"]).Valid() {
+		return errors.Errorf("Unsupported label color '%s'", args["color"])
+	}
 	err := b.client.Post(path, args, &label)
 	if err == nil {
 		label.SetClient(b.client)
@@ -53,6 +102,30 @@ func (b *Board) CreateLabel(label *Label, extraArgs ...Arguments) error {
 	return err
 }
 
+// AddLabelToCards adds the label with the given id to every card in cardIDs.
+// At most Client.BatchConcurrency requests are in flight at once, and each of
+// them waits for the client's rate limiter. The returned map holds an entry
+// for every card which couldn't be labeled, so a failing card doesn't stop
+// the others; IsNotFound() and friends work on those errors. An overall error
+// is only returned when none of the cards could be labeled.
+func (c *Client) AddLabelToCards(labelID string, cardIDs []string) (map[string]error, error) {
+	errs := c.forEachConcurrently(len(cardIDs), func(i int) error {
+		path := fmt.Sprintf("cards/%s/idLabels", cardIDs[i])
+		return c.Post(path, Arguments{"value": labelID}, nil)
+	})
+
+	failed := map[string]error{}
+	for i, err := range errs {
+		if err != nil {
+			failed[cardIDs[i]] = err
+		}
+	}
+	if len(cardIDs) > 0 && len(failed) == len(cardIDs) {
+		return failed, errors.Errorf("Failed to add label %s to any of %d cards", labelID, len(cardIDs))
+	}
+	return failed, nil
+}
+
 // SetClient can be used to override this Label's internal connection to the
 // Trello API. Normally, this is set automatically after API calls.
 func (l *Label) SetClient(newClient *Client) {
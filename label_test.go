@@ -6,6 +6,9 @@
 package trello
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 )
 
@@ -46,6 +49,49 @@ func TestCreateLabel(t *testing.T) {
 	}
 }
 
+func TestCreateLabelWithoutColor(t *testing.T) {
+	board := testBoard(t)
+	label := Label{Name: "Visited", Color: string(LabelColorNone)}
+	board.client.BaseURL = mockResponse("labels", "labels-api-example.json").URL
+	err := board.CreateLabel(&label)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateLabelInvalidColor(t *testing.T) {
+	board := testBoard(t)
+	requests := 0
+	server := NewMockResponder(t, "labels", "labels-api-example.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		requests++
+	})
+	board.client.BaseURL = server.URL()
+
+	label := Label{Name: "Visited", Color: "gren"}
+	err := board.CreateLabel(&label)
+	if err == nil {
+		t.Error("Expected an error for the color 'gren'")
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request for an invalid color, got %d", requests)
+	}
+}
+
+func TestLabelColorValid(t *testing.T) {
+	for _, color := range []LabelColor{LabelColorGreen, LabelColorBlack, LabelColorNone, "sky_light", "red_dark"} {
+		if !color.Valid() {
+			t.Errorf("Expected '%s' to be valid", color)
+		}
+	}
+	for _, color := range []LabelColor{"gren", "Green", "white", "_light"} {
+		if color.Valid() {
+			t.Errorf("Expected '%s' to be invalid", color)
+		}
+	}
+}
+
 func TestLabelSetClient(t *testing.T) {
 	l := Label{}
 	client := testClient()
@@ -66,3 +112,59 @@ func testLabel(t *testing.T) *Label {
 	}
 	return label
 }
+
+func TestAddLabelToCards(t *testing.T) {
+	c := testClient()
+	var mu sync.Mutex
+	labeled := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if value := r.URL.Query().Get("value"); value != "5f80c0a7b6e2d3c4b5a0a0f1" {
+			t.Errorf("Expected the label id as value, got '%s'", value)
+		}
+		if r.URL.Path == "/cards/5f80c0a7b6e2d3c4b5a0c002/idLabels" {
+			http.Error(rw, "The requested resource was not found.", http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		labeled[r.URL.Path] = true
+		mu.Unlock()
+		rw.Write([]byte(`["5f80c0a7b6e2d3c4b5a0a0f1"]`))
+	}))
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	cardIDs := []string{"5f80c0a7b6e2d3c4b5a0c001", "5f80c0a7b6e2d3c4b5a0c002", "5f80c0a7b6e2d3c4b5a0c003"}
+	errs, err := c.AddLabelToCards("5f80c0a7b6e2d3c4b5a0a0f1", cardIDs)
+	if err != nil {
+		t.Fatalf("Didn't expect an overall error on partial success, got %v", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected a single failed card, got %v", errs)
+	}
+	if !IsNotFound(errs["5f80c0a7b6e2d3c4b5a0c002"]) {
+		t.Errorf("Expected a not found error for the missing card, got %v", errs["5f80c0a7b6e2d3c4b5a0c002"])
+	}
+	for _, id := range []string{"5f80c0a7b6e2d3c4b5a0c001", "5f80c0a7b6e2d3c4b5a0c003"} {
+		if !labeled["/cards/"+id+"/idLabels"] {
+			t.Errorf("Expected card %s to be labeled", id)
+		}
+	}
+}
+
+func TestAddLabelToCardsAllFailed(t *testing.T) {
+	c := testClient()
+	server := mockErrorResponse(http.StatusNotFound)
+	defer server.Close()
+	c.BaseURL = server.URL
+
+	errs, err := c.AddLabelToCards("5f80c0a7b6e2d3c4b5a0a0f1", []string{"5f80c0a7b6e2d3c4b5a0c001", "5f80c0a7b6e2d3c4b5a0c002"})
+	if err == nil {
+		t.Error("Expected an overall error when no card could be labeled")
+	}
+	if len(errs) != 2 {
+		t.Errorf("Expected both cards in the error map, got %v", errs)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"time"
+)
+
+// GetListsCached works like GetLists but reuses the lists fetched by an
+// earlier call with the same Arguments if that call is less than ttl ago.
+// It is safe to call concurrently on the same Board. The returned slice is
+// shared between callers of the same cache entry and must not be modified.
+func (b *Board) GetListsCached(ttl time.Duration, extraArgs ...Arguments) ([]*List, error) {
+	cache := lazyTTLCache(&b.listsCache)
+	key := flattenArguments(extraArgs).ToURLValues().Encode()
+	lists, err := cache.get(key, ttl, func() (interface{}, error) {
+		return b.GetLists(extraArgs...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lists.([]*List), nil
+}
@@ -0,0 +1,80 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetListsCached(t *testing.T) {
+	board := testBoard(t)
+
+	server := NewMockResponder(t, "lists", "board-lists-api-example.json")
+	defer server.Close()
+	var mu sync.Mutex
+	requests := 0
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+	})
+	board.client.BaseURL = server.URL()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lists, err := board.GetListsCached(time.Minute, Defaults())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(lists) != 3 {
+				t.Errorf("Expected 3 lists, got %d", len(lists))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("Expected a single request within the TTL, got %d", requests)
+	}
+
+	// A different set of arguments isn't served from the cache
+	_, err := board.GetListsCached(time.Minute, Arguments{"filter": "open"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected a new request for different arguments, got %d requests", requests)
+	}
+}
+
+func TestGetListsCachedExpired(t *testing.T) {
+	board := testBoard(t)
+
+	server := NewMockResponder(t, "lists", "board-lists-api-example.json")
+	defer server.Close()
+	requests := 0
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		requests++
+	})
+	board.client.BaseURL = server.URL()
+
+	for i := 0; i < 2; i++ {
+		_, err := board.GetListsCached(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected every call to hit the server with a zero TTL, got %d requests", requests)
+	}
+}
@@ -23,6 +23,61 @@ func (l *ListDuration) AddDuration(d time.Duration) {
 	l.TimesInList++
 }
 
+// ListTransition represents a Card entering a List. From is empty for the
+// action which placed the card in its first list.
+type ListTransition struct {
+	From string
+	To   string
+	At   time.Time
+}
+
+// ListTransitions fetches the actions which created the card or moved it
+// between lists and returns the card's list transitions, oldest first.
+// Arguments are passed along to GetActions, e.g. Arguments{"since": "..."}.
+func (c *Card) ListTransitions(extraArgs ...Arguments) ([]ListTransition, error) {
+	args := Arguments{"filter": "createCard,copyCard,emailCard,convertToCardFromCheckItem,moveCardToBoard,updateCard:idList"}
+	args.flatten(extraArgs)
+	actions, err := c.GetActions(args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the list changes of card %s", c.ID)
+	}
+	return actions.ListTransitions(), nil
+}
+
+// ListTransitions returns the list transitions of the receiver Actions,
+// oldest first. Actions which neither created a card in a list nor moved it
+// to another list, e.g. updateCard actions of other fields, are skipped.
+func (actions ActionCollection) ListTransitions() []ListTransition {
+	// Trello returns the newest actions first. Actions of the same
+	// millisecond are ordered by their ids, which increase over time.
+	sorted := append(ActionCollection(nil), actions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Date.Equal(sorted[j].Date) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	transitions := make([]ListTransition, 0, len(sorted))
+	for _, action := range sorted {
+		if action.Data == nil {
+			continue
+		}
+		switch {
+		case action.DidCreateCard() && action.Data.List != nil:
+			transitions = append(transitions, ListTransition{To: action.Data.List.ID, At: action.Date})
+		case action.Type == "updateCard" && action.Data.ListAfter != nil:
+			transition := ListTransition{To: action.Data.ListAfter.ID, At: action.Date}
+			if action.Data.ListBefore != nil {
+				transition.From = action.Data.ListBefore.ID
+			}
+			transitions = append(transitions, transition)
+		}
+	}
+
+	return transitions
+}
+
 // GetListDurations analyses a Card's actions to figure out how long it was in each List.
 // It returns a slice of the ListDurations, one Duration per list, or an error.
 func (c *Card) GetListDurations() (durations []*ListDuration, err error) {
@@ -116,3 +116,30 @@ func TestListDurationsForRevivedCard(t *testing.T) {
 		t.Errorf("Expected 62.0 minutes in Approved Work, got %.2f", d2.Duration.Minutes())
 	}
 }
+
+func TestCardListTransitions(t *testing.T) {
+	card := &Card{ID: "5ff40c0a7b6e2d3c4b5a0010"}
+	card.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	card.client.BaseURL = server.URL()
+
+	transitions, err := card.ListTransitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ListTransition{
+		{From: "", To: "5ff40c0a7b6e2d3c4b5a0001", At: time.Date(2021, 1, 4, 12, 0, 0, 0, time.UTC)},
+		{From: "5ff40c0a7b6e2d3c4b5a0001", To: "5ff40c0a7b6e2d3c4b5a0002", At: time.Date(2021, 1, 5, 9, 15, 0, 0, time.UTC)},
+		{From: "5ff40c0a7b6e2d3c4b5a0002", To: "5ff40c0a7b6e2d3c4b5a0003", At: time.Date(2021, 1, 8, 16, 30, 0, 0, time.UTC)},
+	}
+	if len(transitions) != len(expected) {
+		t.Fatalf("Expected %d transitions, got %d: %v", len(expected), len(transitions), transitions)
+	}
+	for i, transition := range transitions {
+		if transition.From != expected[i].From || transition.To != expected[i].To || !transition.At.Equal(expected[i].At) {
+			t.Errorf("Expected transition %d to be %v, got %v", i, expected[i], transition)
+		}
+	}
+}
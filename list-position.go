@@ -0,0 +1,201 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// defaultPosSpacing is the gap left between positions assigned to cards which
+// are placed after the last card of a list, unless Client.PosSpacing is set.
+const defaultPosSpacing = 65536.0
+
+// SortCards fetches the cards of the receiver List and reorders them remote by
+// "due" (ascending, cards without a due date go last) or by "name". Cards which
+// are already in the right relative order keep their position, so only the
+// minimum number of cards needed to realise the new order get a PUT.
+func (l *List) SortCards(by string, extraArgs ...Arguments) error {
+	var less func(a, b *Card) bool
+	switch by {
+	case "due":
+		less = func(a, b *Card) bool {
+			if a.Due == nil {
+				return false
+			}
+			if b.Due == nil {
+				return true
+			}
+			return a.Due.Before(*b.Due)
+		}
+	case "name":
+		less = func(a, b *Card) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+	default:
+		return errors.Errorf("Unsupported sort order '%s' for list %s", by, l.ID)
+	}
+
+	cards, err := l.GetCards(extraArgs...)
+	if err != nil {
+		return errors.Wrapf(err, "SortCards() failed to get the cards of list %s", l.ID)
+	}
+
+	// Start from the current order so the sort is stable with respect to it
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Pos < cards[j].Pos })
+	sort.SliceStable(cards, func(i, j int) bool { return less(cards[i], cards[j]) })
+
+	positions := make([]float64, len(cards))
+	for i, card := range cards {
+		positions[i] = card.Pos
+	}
+
+	for i, pos := range reorderedPositions(positions, l.client.posSpacing()) {
+		if pos == cards[i].Pos {
+			continue
+		}
+		err = cards[i].SetPos(pos)
+		if err != nil {
+			return errors.Wrapf(err, "SortCards() failed to move card %s", cards[i].ID)
+		}
+	}
+	return nil
+}
+
+// TopPos fetches the cards of the receiver List and returns a position which
+// places a card above all of them (half the position of the current first
+// card), without moving any other card.
+func (l *List) TopPos(extraArgs ...Arguments) (float64, error) {
+	min, _, err := l.cardPosRange(extraArgs...)
+	if err != nil || min == 0 {
+		return l.client.posSpacing(), err
+	}
+	return min / 2, nil
+}
+
+// BottomPos fetches the cards of the receiver List and returns a position
+// which places a card below all of them.
+func (l *List) BottomPos(extraArgs ...Arguments) (float64, error) {
+	_, max, err := l.cardPosRange(extraArgs...)
+	if err != nil {
+		return l.client.posSpacing(), err
+	}
+	return max + l.client.posSpacing(), nil
+}
+
+// NormalizePositions fetches the cards of the receiver List and rewrites their
+// positions to evenly spaced values (multiples of Client.PosSpacing), keeping
+// their current order. Repeatedly moving cards between neighbours halves the
+// gaps between positions each time, this restores room after many reorders.
+// Only cards whose position changes get a PUT.
+func (l *List) NormalizePositions(extraArgs ...Arguments) error {
+	cards, err := l.GetCards(extraArgs...)
+	if err != nil {
+		return errors.Wrapf(err, "NormalizePositions() failed to get the cards of list %s", l.ID)
+	}
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Pos < cards[j].Pos })
+
+	spacing := l.client.posSpacing()
+	for i, card := range cards {
+		pos := float64(i+1) * spacing
+		if pos == card.Pos {
+			continue
+		}
+		err = card.SetPos(pos)
+		if err != nil {
+			return errors.Wrapf(err, "NormalizePositions() failed to move card %s", card.ID)
+		}
+	}
+	return nil
+}
+
+// cardPosRange returns the lowest and highest position of the cards in the
+// list, both zero when the list is empty.
+func (l *List) cardPosRange(extraArgs ...Arguments) (min, max float64, err error) {
+	cards, err := l.GetCards(extraArgs...)
+	if err != nil {
+		err = errors.Wrapf(err, "Failed to get the card positions of list %s", l.ID)
+		return
+	}
+	for i, card := range cards {
+		if i == 0 || card.Pos < min {
+			min = card.Pos
+		}
+		if i == 0 || card.Pos > max {
+			max = card.Pos
+		}
+	}
+	return
+}
+
+// reorderedPositions takes the current positions of cards in their desired
+// order and returns strictly increasing positions for them. The longest run of
+// positions which is already increasing is kept, everything else is spread
+// between its neighbours (or appended after the last one, spacing apart).
+func reorderedPositions(current []float64, spacing float64) []float64 {
+	keep := longestIncreasing(current)
+	positions := make([]float64, len(current))
+
+	prev := 0.0
+	for i := 0; i < len(current); {
+		if keep[i] {
+			positions[i] = current[i]
+			prev = current[i]
+			i++
+			continue
+		}
+
+		// Find the run of cards to be moved and the next kept position
+		j := i
+		for j < len(current) && !keep[j] {
+			j++
+		}
+		step := spacing
+		if j < len(current) {
+			step = (current[j] - prev) / float64(j-i+1)
+		}
+		for k := i; k < j; k++ {
+			prev += step
+			positions[k] = prev
+		}
+		i = j
+	}
+	return positions
+}
+
+// longestIncreasing marks the elements of the longest strictly increasing
+// subsequence of values.
+func longestIncreasing(values []float64) []bool {
+	keep := make([]bool, len(values))
+	if len(values) == 0 {
+		return keep
+	}
+
+	// tails[k] holds the index of the smallest tail of an increasing
+	// subsequence of length k+1, prev links each element to its predecessor.
+	tails := []int{}
+	prev := make([]int, len(values))
+	for i, v := range values {
+		k := sort.Search(len(tails), func(k int) bool { return values[tails[k]] >= v })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		keep[i] = true
+	}
+	return keep
+}
@@ -0,0 +1,189 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSortCardsByDue(t *testing.T) {
+	list := testList(t)
+	server, puts := mockListPositionResponse(t, "cards-unsorted-by-due.json")
+	defer server.Close()
+	list.client.BaseURL = server.URL
+
+	err := list.SortCards("due", Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(puts) != 1 {
+		t.Fatalf("Expected a single card to be moved, got %d PUTs: %v", len(puts), puts)
+	}
+
+	if _, ok := puts["5f1a0c0a7b6e2d3c4b5a0001"]; !ok {
+		t.Errorf("Expected card 5f1a0c0a7b6e2d3c4b5a0001 to be moved, got %v", puts)
+	}
+
+	// Fetch again and apply the new positions to check the resulting order
+	cards, err := list.GetCards(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, card := range cards {
+		if pos, ok := puts[card.ID]; ok {
+			card.Pos = pos
+		}
+	}
+	expected := []string{
+		"5f1a0c0a7b6e2d3c4b5a0002",
+		"5f1a0c0a7b6e2d3c4b5a0003",
+		"5f1a0c0a7b6e2d3c4b5a0001",
+		"5f1a0c0a7b6e2d3c4b5a0004",
+	}
+	byID := map[string]*Card{}
+	for _, card := range cards {
+		byID[card.ID] = card
+	}
+	for i := 1; i < len(expected); i++ {
+		if byID[expected[i-1]].Pos >= byID[expected[i]].Pos {
+			t.Errorf("Expected card %s (pos %v) before card %s (pos %v)", expected[i-1], byID[expected[i-1]].Pos, expected[i], byID[expected[i]].Pos)
+		}
+	}
+}
+
+func TestSortCardsAlreadySorted(t *testing.T) {
+	list := testList(t)
+	server, puts := mockListPositionResponse(t, "cards-sorted-by-due.json")
+	defer server.Close()
+	list.client.BaseURL = server.URL
+
+	err := list.SortCards("due", Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(puts) != 0 {
+		t.Errorf("Expected no PUTs for an already sorted list, got %v", puts)
+	}
+}
+
+func TestSortCardsUnsupportedOrder(t *testing.T) {
+	list := testList(t)
+	err := list.SortCards("color", Defaults())
+	if err == nil {
+		t.Error("Expected an error for an unsupported sort order")
+	}
+}
+
+func TestListTopAndBottomPos(t *testing.T) {
+	list := testList(t)
+	list.client.BaseURL = mockResponse("lists", "cards-unsorted-by-due.json").URL
+
+	top, err := list.TopPos(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top != 8192 {
+		t.Errorf("Expected top pos 8192, got %v", top)
+	}
+
+	bottom, err := list.BottomPos(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bottom != 131072 {
+		t.Errorf("Expected bottom pos 131072, got %v", bottom)
+	}
+}
+
+func TestNormalizePositions(t *testing.T) {
+	list := testList(t)
+	server, puts := mockListPositionResponse(t, "cards-crowded.json")
+	defer server.Close()
+	list.client.BaseURL = server.URL
+
+	err := list.NormalizePositions(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]float64{
+		"5f1a0c0a7b6e2d3c4b5a0013": 65536,
+		"5f1a0c0a7b6e2d3c4b5a0011": 131072,
+		"5f1a0c0a7b6e2d3c4b5a0012": 196608,
+	}
+	if len(puts) != len(expected) {
+		t.Errorf("Expected %d cards to be moved, got %v", len(expected), puts)
+	}
+	for id, pos := range expected {
+		if puts[id] != pos {
+			t.Errorf("Expected card %s to be moved to %v, got %v", id, pos, puts[id])
+		}
+	}
+}
+
+func TestNormalizePositionsWithCustomSpacing(t *testing.T) {
+	list := testList(t)
+	server, puts := mockListPositionResponse(t, "cards-crowded.json")
+	defer server.Close()
+	list.client.BaseURL = server.URL
+	list.client.PosSpacing = 1024
+
+	err := list.NormalizePositions(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(puts) != 4 {
+		t.Fatalf("Expected all 4 cards to be moved, got %v", puts)
+	}
+	if puts["5f1a0c0a7b6e2d3c4b5a0014"] != 4096 {
+		t.Errorf("Expected the last card at 4096, got %v", puts["5f1a0c0a7b6e2d3c4b5a0014"])
+	}
+}
+
+func TestReorderedPositions(t *testing.T) {
+	positions := reorderedPositions([]float64{300, 100, 200, 400}, defaultPosSpacing)
+	for i := 1; i < len(positions); i++ {
+		if positions[i-1] >= positions[i] {
+			t.Errorf("Expected increasing positions, got %v", positions)
+		}
+	}
+	if positions[1] != 100 || positions[2] != 200 || positions[3] != 400 {
+		t.Errorf("Expected the increasing run to keep its positions, got %v", positions)
+	}
+}
+
+// mockListPositionResponse serves the named file from testdata/lists for
+// every GET and echoes the pos of every PUT to cards/{id}. The returned map
+// records the pos sent for each card ID.
+func mockListPositionResponse(t *testing.T, filename string) (*httptest.Server, map[string]float64) {
+	mockData, err := ioutil.ReadFile(filepath.Join(".", "testdata", "lists", filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	puts := map[string]float64{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			rw.Write(mockData)
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/cards/")
+		var pos float64
+		fmt.Sscanf(r.URL.Query().Get("pos"), "%g", &pos)
+		puts[id] = pos
+		fmt.Fprintf(rw, `{"id": "%s", "pos": %g}`, id, pos)
+	}))
+	return server, puts
+}
@@ -20,6 +20,7 @@ type List struct {
 	Closed     bool    `json:"closed"`
 	Pos        float32 `json:"pos,omitempty"`
 	Subscribed bool    `json:"subscribed"`
+	SoftLimit  *int    `json:"softLimit,omitempty"`
 	Board      *Board  `json:"board,omitempty"`
 	Cards      []*Card `json:"cards,omitempty"`
 }
@@ -45,6 +46,8 @@ func (l *List) CreatedAt() time.Time {
 }
 
 // GetList takes a list's id and Arguments and returns the matching list.
+// Arguments are forwarded to the API, so e.g. Arguments{"cards": "open"}
+// loads the list's cards inline and Arguments{"board": "true"} its board.
 func (c *Client) GetList(listID string, extraArgs ...Arguments) (list *List, err error) {
 	args := flattenArguments(extraArgs)
 	path := fmt.Sprintf("lists/%s", listID)
@@ -55,9 +58,24 @@ func (c *Client) GetList(listID string, extraArgs ...Arguments) (list *List, err
 	return
 }
 
+// GetList takes Arguments and returns the list the receiver Card is in.
+// The list is resolved by Trello, so IDList doesn't need to be loaded.
+func (c *Card) GetList(extraArgs ...Arguments) (list *List, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("cards/%s/list", c.ID)
+	err = c.client.Get(path, args, &list)
+	if list != nil {
+		list.SetClient(c.client)
+	}
+	return
+}
+
 // GetLists takes Arguments and returns the lists of the receiver Board.
+// Only open lists are returned by default, Arguments{"filter": "closed"} or
+// Arguments{"filter": "all"} include the archived ones.
 func (b *Board) GetLists(extraArgs ...Arguments) (lists []*List, err error) {
-	args := flattenArguments(extraArgs)
+	args := Arguments{"filter": "open"}
+	args.flatten(extraArgs)
 	path := fmt.Sprintf("boards/%s/lists", b.ID)
 	err = b.client.Get(path, args, &lists)
 	for i := range lists {
@@ -66,6 +84,31 @@ func (b *Board) GetLists(extraArgs ...Arguments) (lists []*List, err error) {
 	return
 }
 
+// GetOpenLists takes Arguments and returns the open lists of the receiver
+// Board, whatever filter the Arguments ask for.
+func (b *Board) GetOpenLists(extraArgs ...Arguments) ([]*List, error) {
+	args := flattenArguments(extraArgs)
+	args["filter"] = "open"
+	return b.GetLists(args)
+}
+
+// GetListsWithCards takes Arguments and returns the open lists of the
+// receiver Board with their open cards in Cards, loaded in a single request.
+// The card attributes can be picked with Arguments{"card_fields": "..."}, and
+// Arguments{"cards": "all"} includes archived cards. Every card gets the
+// Client and its List.
+func (b *Board) GetListsWithCards(extraArgs ...Arguments) (lists []*List, err error) {
+	args := Arguments{"cards": "open"}
+	args.flatten(extraArgs)
+	lists, err = b.GetLists(args)
+	for _, list := range lists {
+		for _, card := range list.Cards {
+			card.List = list
+		}
+	}
+	return
+}
+
 // CreateList creates a list.
 // Attribute currently supported as extra argument: pos.
 // Attributes currently known to be unsupported: idListSource.
@@ -100,6 +143,9 @@ func (b *Board) CreateList(name string, extraArgs ...Arguments) (list *List, err
 }
 
 // Update UPDATEs the list's attributes.
+// Attributes currently supported as extra argument: name, closed, idBoard,
+// pos, subscribed, softLimit (the WIP limit, empty to remove it).
+//
 // API Docs: https://developers.trello.com/reference/#listsid-1
 func (l *List) Update(extraArgs ...Arguments) error {
 	args := flattenArguments(extraArgs)
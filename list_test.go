@@ -6,6 +6,7 @@
 package trello
 
 import (
+	"net/http"
 	"testing"
 	"time"
 )
@@ -43,6 +44,175 @@ func TestGetListWithCards(t *testing.T) {
 	}
 }
 
+func TestGetListThenGetCards(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t)
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	list, err := c.GetList("5ccd793e91682684235c0b13", Arguments{"cards": "open"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Name != "In Progress" {
+		t.Errorf("Expected list name 'In Progress', got '%s'", list.Name)
+	}
+	if list.IDBoard != "5c41027ca9c378795b5a5036" {
+		t.Errorf("Expected list to pick up board ID, got '%s'", list.IDBoard)
+	}
+	if len(list.Cards) != 2 {
+		t.Errorf("Expected 2 inline cards, got %d", len(list.Cards))
+	}
+	if list.client == nil {
+		t.Fatal("Expected list to pick up a client")
+	}
+
+	cards, err := list.GetCards()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Errorf("Expected 2 cards, got %d", len(cards))
+	}
+	if cards[0].IDList != list.ID {
+		t.Errorf("Expected card to be in list %s, got '%s'", list.ID, cards[0].IDList)
+	}
+}
+
+func TestGetCardsOnListWithFilter(t *testing.T) {
+	list := &List{ID: "5ccd793e91682684235c0b13"}
+	list.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	list.client.BaseURL = server.URL()
+
+	cards, err := list.GetCards()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Errorf("Expected the 2 open cards by default, got %d", len(cards))
+	}
+
+	cards, err = list.GetCards(Arguments{"filter": "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 3 {
+		t.Fatalf("Expected 3 cards with the 'all' filter, got %d", len(cards))
+	}
+	if !cards[2].Closed {
+		t.Errorf("Expected the archived card to be included")
+	}
+	for _, card := range cards {
+		if card.client == nil {
+			t.Errorf("Expected card %s to have a client", card.ID)
+		}
+	}
+}
+
+func TestGetCardsModifiedSince(t *testing.T) {
+	list := &List{ID: "5ccd793e91682684235c0b13"}
+	list.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	list.client.BaseURL = server.URL()
+
+	since := time.Date(2019, 5, 4, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	cards, err := list.GetCardsModifiedSince(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("Expected 2 modified cards, got %d", len(cards))
+	}
+	if cards[0].ID != "5ccd7a2d31b1e31c4cf17c9d" || cards[1].ID != "5ccd7a3f8e2b1c0d9a4b6e71" {
+		t.Errorf("Expected the renamed and the commented card in list order, got %s and %s", cards[0].ID, cards[1].ID)
+	}
+	if cards[1].client == nil {
+		t.Error("Expected the cards to have a client")
+	}
+}
+
+func TestGetListsOnBoardWithFilter(t *testing.T) {
+	board := &Board{ID: "60400c0a7b6e2d3c4b5a00b0"}
+	board.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	board.client.BaseURL = server.URL()
+
+	lists, err := board.GetLists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists) != 2 {
+		t.Errorf("Expected the 2 open lists by default, got %d", len(lists))
+	}
+
+	lists, err = board.GetLists(Arguments{"filter": "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists) != 3 {
+		t.Fatalf("Expected 3 lists with the 'all' filter, got %d", len(lists))
+	}
+	if !lists[1].Closed {
+		t.Error("Expected the archived list to be included")
+	}
+	for _, list := range lists {
+		if list.client == nil {
+			t.Errorf("Expected list %s to have a client", list.ID)
+		}
+	}
+
+	lists, err = board.GetOpenLists(Arguments{"filter": "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists) != 2 || lists[0].client == nil {
+		t.Errorf("Expected GetOpenLists to return the 2 open lists, got %d", len(lists))
+	}
+}
+
+func TestBoardGetListsWithCards(t *testing.T) {
+	board := &Board{ID: "60400c0a7b6e2d3c4b5a00b0"}
+	board.SetClient(testClient())
+	requests := 0
+	server := NewMockResponder(t)
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		requests++
+	})
+	board.client.BaseURL = server.URL()
+
+	lists, err := board.GetListsWithCards(Arguments{"card_fields": "id,name,idList,pos"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+	if len(lists) != 2 {
+		t.Fatalf("Expected 2 lists, got %d", len(lists))
+	}
+	if len(lists[0].Cards) != 2 || len(lists[1].Cards) != 1 {
+		t.Fatalf("Expected 2 and 1 cards, got %d and %d", len(lists[0].Cards), len(lists[1].Cards))
+	}
+	if lists[1].Cards[0].Name != "Fix the build" {
+		t.Errorf("Expected 'Fix the build' in the second list, got '%s'", lists[1].Cards[0].Name)
+	}
+	for _, list := range lists {
+		if list.client == nil {
+			t.Errorf("Expected list %s to have a client", list.ID)
+		}
+		for _, card := range list.Cards {
+			if card.client == nil || card.List != list || card.IDList != list.ID {
+				t.Errorf("Expected card %s to have a client and list %s", card.ID, list.ID)
+			}
+		}
+	}
+}
+
 func TestGetListsOnBoard(t *testing.T) {
 	board := testBoard(t)
 	board.client.BaseURL = mockResponse("lists", "board-lists-api-example.json").URL
@@ -174,6 +344,35 @@ func TestUpdateList(t *testing.T) {
 	}
 }
 
+func TestUpdateListSubscribedAndSoftLimit(t *testing.T) {
+	l := testList(t)
+	server := NewMockResponder(t, "lists", "list-subscribed.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected a PUT, got %s", r.Method)
+		}
+		if r.URL.Query().Get("subscribed") != "true" {
+			t.Errorf("Expected subscribed=true, got '%s'", r.URL.Query().Get("subscribed"))
+		}
+		if r.URL.Query().Get("softLimit") != "5" {
+			t.Errorf("Expected softLimit=5, got '%s'", r.URL.Query().Get("softLimit"))
+		}
+	})
+	defer server.Close()
+	l.client.BaseURL = server.URL()
+
+	err := l.Update(Arguments{"subscribed": "true", "softLimit": "5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.Subscribed {
+		t.Error("Expected list to be subscribed")
+	}
+	if l.SoftLimit == nil || *l.SoftLimit != 5 {
+		t.Errorf("Expected list to pick up a soft limit of 5, got %v", l.SoftLimit)
+	}
+}
+
 func TestArchiveUnarchiveList(t *testing.T) {
 	l := testList(t)
 
@@ -202,3 +401,23 @@ func TestListSetClient(t *testing.T) {
 		t.Error("Expected non-nil list.client")
 	}
 }
+
+func TestCardGetList(t *testing.T) {
+	card := testCard(t)
+	card.IDList = ""
+
+	server := NewMockResponder(t)
+	defer server.Close()
+	card.client.BaseURL = server.URL()
+
+	list, err := card.GetList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.ID != "4eea4ffc91e31d174600004a" || list.Name != "To Do Soon" {
+		t.Errorf("Unexpected list %s '%s'", list.ID, list.Name)
+	}
+	if list.client != card.client {
+		t.Error("Expected the list to carry the card's client")
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownBullet   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	markdownNumbered = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	markdownLink     = regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownURL      = regexp.MustCompile(`^https?://[^\s<]+`)
+	markdownMention  = regexp.MustCompile(`^@[A-Za-z0-9_]+`)
+)
+
+// DescHTML returns the card's description rendered to HTML by
+// RenderTrelloMarkdown.
+func (c *Card) DescHTML() string {
+	return RenderTrelloMarkdown(c.Desc)
+}
+
+// RenderTrelloMarkdown renders the subset of markdown Trello supports in
+// card descriptions and comments to HTML: paragraphs (single line breaks are
+// kept), headings, bulleted and numbered lists, fenced code blocks, code
+// spans, bold, italic, links and bare URLs. Like Trello, @username mentions
+// are wrapped in <span class="atMention"> and a hash followed by a number
+// (e.g. #12) is plain text rather than a heading; cards are only linked by
+// their URL. Links only get an href for http, https, mailto and relative
+// targets, others (e.g. javascript: or data:) are rendered as escaped text.
+// Any other HTML in the text is escaped.
+func RenderTrelloMarkdown(desc string) string {
+	var out strings.Builder
+	var paragraph []string
+	list := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			out.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	addListItem := func(tag, text string) {
+		flushParagraph()
+		if list != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			list = tag
+		}
+		out.WriteString("<li>" + renderMarkdownInline(text) + "</li>\n")
+	}
+
+	lines := strings.Split(strings.ReplaceAll(desc, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "```") {
+			flushParagraph()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+			continue
+		}
+
+		if line == "" {
+			flushParagraph()
+			closeList()
+		} else if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			closeList()
+			tag := "h" + string(rune('0'+len(m[1])))
+			out.WriteString("<" + tag + ">" + renderMarkdownInline(m[2]) + "</" + tag + ">\n")
+		} else if m := markdownBullet.FindStringSubmatch(lines[i]); m != nil {
+			addListItem("ul", m[1])
+		} else if m := markdownNumbered.FindStringSubmatch(lines[i]); m != nil {
+			addListItem("ol", m[1])
+		} else {
+			closeList()
+			paragraph = append(paragraph, renderMarkdownInline(line))
+		}
+	}
+	flushParagraph()
+	closeList()
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// renderMarkdownInline renders the inline markdown of a single line.
+func renderMarkdownInline(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch c := s[i]; {
+		case c == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				out.WriteString("<code>" + html.EscapeString(rest[1:1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case c == '[':
+			if m := markdownLink.FindStringSubmatch(rest); m != nil && isSafeMarkdownURL(m[2]) {
+				out.WriteString(`<a href="` + html.EscapeString(m[2]) + `">` + renderMarkdownInline(m[1]) + "</a>")
+				i += len(m[0])
+				continue
+			}
+		case c == 'h' && isMarkdownWordStart(s, i):
+			if link := strings.TrimRight(markdownURL.FindString(rest), ".,;:!?)"); link != "" && isSafeMarkdownURL(link) {
+				out.WriteString(`<a href="` + html.EscapeString(link) + `">` + html.EscapeString(link) + "</a>")
+				i += len(link)
+				continue
+			}
+		case c == '@' && isMarkdownWordStart(s, i):
+			if mention := markdownMention.FindString(rest); mention != "" {
+				out.WriteString(`<span class="atMention">` + mention + "</span>")
+				i += len(mention)
+				continue
+			}
+		case c == '*' || (c == '_' && isMarkdownWordStart(s, i)):
+			delim, tag := rest[:1], "em"
+			if strings.HasPrefix(rest, delim+delim) {
+				delim, tag = delim+delim, "strong"
+			}
+			if end := strings.Index(rest[len(delim):], delim); end > 0 {
+				out.WriteString("<" + tag + ">" + renderMarkdownInline(rest[len(delim):len(delim)+end]) + "</" + tag + ">")
+				i += 2*len(delim) + end
+				continue
+			}
+		}
+		out.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return out.String()
+}
+
+// isSafeMarkdownURL returns true if link may be used as the href of a link:
+// relative URLs and absolute ones with the http, https or mailto scheme.
+func isSafeMarkdownURL(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// isMarkdownWordStart returns true if the byte at i doesn't continue a word,
+// an email address or a domain, so mentions in "jane@example.com" and the
+// underscores of snake_case stay text.
+func isMarkdownWordStart(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	c := s[i-1]
+	isWord := c == '_' || c == '@' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	return !isWord
+}
@@ -0,0 +1,51 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"testing"
+)
+
+func TestRenderTrelloMarkdown(t *testing.T) {
+	tests := []struct {
+		markdown string
+		expected string
+	}{
+		{"Plain text", "<p>Plain text</p>"},
+		{"**bold**, __bold__, *italic* and _italic_", "<p><strong>bold</strong>, <strong>bold</strong>, <em>italic</em> and <em>italic</em></p>"},
+		{"snake_case_name and 2 * 3", "<p>snake_case_name and 2 * 3</p>"},
+		{"See [the docs](https://developer.atlassian.com/cloud/trello/).", `<p>See <a href="https://developer.atlassian.com/cloud/trello/">the docs</a>.</p>`},
+		{"Blocked by https://trello.com/c/GRsvY3vZ.", `<p>Blocked by <a href="https://trello.com/c/GRsvY3vZ">https://trello.com/c/GRsvY3vZ</a>.</p>`},
+		{"Run `go test ./...` with **care**", "<p>Run <code>go test ./...</code> with <strong>care</strong></p>"},
+		{"`<b>*not bold*</b>`", "<p><code>&lt;b&gt;*not bold*&lt;/b&gt;</code></p>"},
+		{"Ping @aaronlongwell, not jane@example.com", `<p>Ping <span class="atMention">@aaronlongwell</span>, not jane@example.com</p>`},
+		{"Duplicate of #12", "<p>Duplicate of #12</p>"},
+		{"# Release\n## Steps", "<h1>Release</h1>\n<h2>Steps</h2>"},
+		{"First line\nsecond line\n\nNext paragraph", "<p>First line<br>\nsecond line</p>\n<p>Next paragraph</p>"},
+		{"- one\n- **two**\n\n1. first\n2. second", "<ul>\n<li>one</li>\n<li><strong>two</strong></li>\n</ul>\n<ol>\n<li>first</li>\n<li>second</li>\n</ol>"},
+		{"```\nif a < b {\n\treturn\n}\n```", "<pre><code>if a &lt; b {\n\treturn\n}</code></pre>"},
+		{"<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"[mail me](mailto:aaron@example.com) or see [notes](/b/cI66RoQS/notes)", `<p><a href="mailto:aaron@example.com">mail me</a> or see <a href="/b/cI66RoQS/notes">notes</a></p>`},
+		{"[click](javascript:alert(1))", "<p>[click](javascript:alert(1))</p>"},
+		{"[click](JaVaScRiPt:alert(1))", "<p>[click](JaVaScRiPt:alert(1))</p>"},
+		{"[click](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)", "<p>[click](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)</p>"},
+		{`[x](javascript:alert("<b>"))`, "<p>[x](javascript:alert(&#34;&lt;b&gt;&#34;))</p>"},
+	}
+
+	for _, test := range tests {
+		html := RenderTrelloMarkdown(test.markdown)
+		if html != test.expected {
+			t.Errorf("Expected %q to render as\n%s\ngot\n%s", test.markdown, test.expected, html)
+		}
+	}
+}
+
+func TestCardDescHTML(t *testing.T) {
+	card := &Card{Desc: "Ask @aaronlongwell"}
+	if html := card.DescHTML(); html != `<p>Ask <span class="atMention">@aaronlongwell</span></p>` {
+		t.Errorf("Unexpected description HTML %s", html)
+	}
+}
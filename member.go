@@ -7,6 +7,7 @@ package trello
 
 import (
 	"fmt"
+	"io"
 )
 
 // Member represents a Trello member.
@@ -18,6 +19,7 @@ type Member struct {
 	FullName        string   `json:"fullName"`
 	Initials        string   `json:"initials"`
 	AvatarHash      string   `json:"avatarHash"`
+	AvatarURL       string   `json:"avatarUrl"`
 	Email           string   `json:"email"`
 	IDBoards        []string `json:"idBoards"`
 	IDOrganizations []string `json:"idOrganizations"`
@@ -67,6 +69,8 @@ func (b *Board) GetMembers(extraArgs ...Arguments) (members []*Member, err error
 }
 
 // GetMembers takes Arguments and returns a slice of all members of the Card or an error.
+// Unlike the IDMembers of the card, the members carry their details (username,
+// fullName, avatar, ...). They're stored in the card's Members as well.
 func (c *Card) GetMembers(extraArgs ...Arguments) (members []*Member, err error) {
 	args := flattenArguments(extraArgs)
 	path := fmt.Sprintf("cards/%s/members", c.ID)
@@ -74,9 +78,41 @@ func (c *Card) GetMembers(extraArgs ...Arguments) (members []*Member, err error)
 	for i := range members {
 		members[i].SetClient(c.client)
 	}
+	if err == nil {
+		c.Members = members
+	}
 	return
 }
 
+// GetVotes takes Arguments and returns the members who voted on the
+// receiver Card.
+func (c *Card) GetVotes(extraArgs ...Arguments) (members []*Member, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("cards/%s/membersVoted", c.ID)
+	err = c.client.Get(path, args, &members)
+	for i := range members {
+		members[i].SetClient(c.client)
+	}
+	return
+}
+
+// Update PUTs the given attributes (e.g. fullName, initials, bio) of the
+// receiver Member and updates the struct from the response. Trello only
+// permits updating the member the token belongs to, other members result in
+// an error for which IsPermissionDenied() is true.
+func (m *Member) Update(extraArgs ...Arguments) error {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("members/%s", m.ID)
+	return m.client.Put(path, args, m)
+}
+
+// UploadAvatar takes an image io.Reader and its mime type (e.g. "image/png")
+// and uploads it as the receiver Member's avatar.
+func (m *Member) UploadAvatar(r io.Reader, mimeType string) error {
+	path := fmt.Sprintf("members/%s/avatar", m.ID)
+	return m.client.postFile(path, Defaults(), nil, "avatar", mimeType, r)
+}
+
 // SetClient can be used to override this Member's internal connection to the
 // Trello API. Normally, this is set automatically after API calls.
 func (m *Member) SetClient(newClient *Client) {
@@ -6,7 +6,10 @@
 package trello
 
 import (
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetMembersOnBoard(t *testing.T) {
@@ -48,6 +51,103 @@ func TestGetMembersOnCard(t *testing.T) {
 	}
 }
 
+func TestGetMembersOnCardWithDetails(t *testing.T) {
+	card := testCard(t)
+	server := mockResponse("members", "card-members-with-details.json")
+	defer server.Close()
+	card.client.BaseURL = server.URL
+
+	members, err := card.GetMembers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("Expected 2 members, got %d", len(members))
+	}
+	if members[0].Username != "aaronlongwell" || members[0].FullName != "Aaron Longwell" {
+		t.Errorf("Unexpected first member %+v", members[0])
+	}
+	if members[0].AvatarURL == "" || members[1].AvatarURL != "" {
+		t.Errorf("Expected only the first member to have an avatar, got '%s' and '%s'", members[0].AvatarURL, members[1].AvatarURL)
+	}
+	if len(card.Members) != 2 || card.Members[1] != members[1] {
+		t.Errorf("Expected the members to be stored on the card, got %v", card.Members)
+	}
+	for _, member := range members {
+		if member.client == nil {
+			t.Errorf("Expected member %s to have a client", member.ID)
+		}
+	}
+}
+
+func TestMemberUpdate(t *testing.T) {
+	c := testClient()
+	c.BaseURL = mockResponse("members", "api-example.json").URL
+	member, err := c.GetMember("me", Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewMockResponder(t, "members", "member-updated.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected a PUT, got %s", r.Method)
+		}
+		if r.URL.Query().Get("fullName") != "Bob Tester Jr." {
+			t.Errorf("Expected fullName 'Bob Tester Jr.', got '%s'", r.URL.Query().Get("fullName"))
+		}
+	})
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	err = member.Update(Arguments{"fullName": "Bob Tester Jr."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member.FullName != "Bob Tester Jr." {
+		t.Errorf("Expected member to pick up the new full name, got '%s'", member.FullName)
+	}
+	if member.Initials != "BTJ" {
+		t.Errorf("Expected member to pick up the new initials, got '%s'", member.Initials)
+	}
+}
+
+func TestMemberUpdateUnauthorized(t *testing.T) {
+	member := Member{ID: "4ee7deffe582acdec80000ac"}
+	member.SetClient(testClient())
+	member.client.BaseURL = mockErrorResponse(401).URL
+
+	err := member.Update(Arguments{"fullName": "Somebody Else"})
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission denied error, got %v", err)
+	}
+}
+
+func TestMemberUploadAvatar(t *testing.T) {
+	member := Member{ID: "me"}
+	member.SetClient(testClient())
+	server := NewMockResponder(t, "members", "member-updated.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/members/me/avatar" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Header.Get("Content-Type") != "image/png" {
+			t.Errorf("Expected part Content-Type image/png, got '%s'", header.Header.Get("Content-Type"))
+		}
+	})
+	defer server.Close()
+	member.client.BaseURL = server.URL()
+
+	err := member.UploadAvatar(strings.NewReader("not really a png"), "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestMemberSetClient(t *testing.T) {
 	m := Member{}
 	client := testClient()
@@ -56,3 +156,75 @@ func TestMemberSetClient(t *testing.T) {
 		t.Error("Expected non-nil Member.client")
 	}
 }
+
+func TestMemberCardsByBoard(t *testing.T) {
+	member := &Member{ID: "4ee7deffe582acdec80000ac"}
+	member.SetClient(testClient())
+	requests := 0
+	server := NewMockResponder(t)
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		requests++
+	})
+	member.client.BaseURL = server.URL()
+
+	byBoard, err := member.CardsByBoard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+	if len(byBoard) != 2 {
+		t.Fatalf("Expected cards of 2 boards, got %d", len(byBoard))
+	}
+	if cards := byBoard["4ed7e27fe6abb2517a21383d"]; len(cards) != 3 || cards[2].Name != "Fix the build" {
+		t.Errorf("Expected 3 cards on board 4ed7e27fe6abb2517a21383d, got %d", len(cards))
+	}
+	if cards := byBoard["60400c0a7b6e2d3c4b5a00b0"]; len(cards) != 2 || cards[0].Name != "Plan the sprint" {
+		t.Errorf("Expected 2 cards on board 60400c0a7b6e2d3c4b5a00b0, got %d", len(cards))
+	}
+	for _, cards := range byBoard {
+		for _, card := range cards {
+			if card.client == nil {
+				t.Errorf("Expected card %s to have a client", card.ID)
+			}
+		}
+	}
+}
+
+func TestMemberGetCardsDueBefore(t *testing.T) {
+	c := testClient()
+	member := &Member{ID: "4ee7df1be582acdec80000ae"}
+	member.SetClient(c)
+	server := NewMockResponder(t)
+	defer server.Close()
+	c.BaseURL = server.URL()
+
+	// Midnight in Buenos Aires is 03:00 UTC: a card due at 22:00-03:00 on
+	// September 4th (01:00 UTC on the 5th) is before the cutoff, one due at
+	// 04:30+01:00 on the 5th (03:30 UTC) isn't.
+	cutoff := time.Date(2020, 9, 5, 0, 0, 0, 0, time.FixedZone("ART", -3*60*60))
+
+	cards, err := member.GetCardsDueBefore(cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"5fc10c0a7b6e2d3c4b5a0005", "5fc10c0a7b6e2d3c4b5a0003", "5fc10c0a7b6e2d3c4b5a0006"}
+	if len(cards) != len(expected) {
+		t.Fatalf("Expected %d cards, got %d", len(expected), len(cards))
+	}
+	for i, id := range expected {
+		if cards[i].ID != id {
+			t.Errorf("Expected card %s at position %d, got %s", id, i, cards[i].ID)
+		}
+	}
+
+	cards, err = member.GetCardsDueBefore(cutoff, Arguments{"includeDueComplete": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 4 || cards[1].ID != "5fc10c0a7b6e2d3c4b5a0004" {
+		t.Errorf("Expected the completed card to be included in order, got %d cards", len(cards))
+	}
+}
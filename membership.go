@@ -1,5 +1,7 @@
 package trello
 
+import "fmt"
+
 // Membership represents a Trello membership.
 // https://developers.trello.com/reference#memberships-nested-resource
 type Membership struct {
@@ -8,4 +10,33 @@ type Membership struct {
 	Type        string `json:"memberType"`
 	Unconfirmed bool   `json:"unconfirmed"`
 	Deactivated bool   `json:"deactivated"`
+
+	// Member is only loaded with Arguments{"member": "true"}.
+	Member *Member `json:"member,omitempty"`
+}
+
+// GetMemberships takes Arguments and returns the memberships of the receiver
+// Organization. Type is "admin" or "normal", deactivated accounts have
+// Deactivated set.
+func (o *Organization) GetMemberships(extraArgs ...Arguments) (memberships []*Membership, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("organizations/%s/memberships", o.ID)
+	err = o.client.Get(path, args, &memberships)
+	return
+}
+
+// GetMemberships takes Arguments and returns the memberships of the receiver
+// Board. Only active memberships are returned unless Arguments{"filter":
+// "all"} is given, deactivated ones have Deactivated set. The member details
+// are inlined into Member with Arguments{"member": "true"}.
+func (b *Board) GetMemberships(extraArgs ...Arguments) (memberships []*Membership, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("boards/%s/memberships", b.ID)
+	err = b.client.Get(path, args, &memberships)
+	for _, membership := range memberships {
+		if membership.Member != nil {
+			membership.Member.SetClient(b.client)
+		}
+	}
+	return
 }
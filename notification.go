@@ -1,7 +1,10 @@
 package trello
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // Notification represents a Trello Notification.
@@ -53,6 +56,27 @@ func (c *Client) GetMyNotifications(extraArgs ...Arguments) (notifications []*No
 	return
 }
 
+// UnreadNotificationCount returns the number of unread notifications of the
+// receiver Member, which has to be the authenticated user (or "me"). Only the
+// notification IDs are requested, and at most 1000 of them are counted.
+func (m *Member) UnreadNotificationCount() (int, error) {
+	args := Arguments{
+		"filter":      "all",
+		"read_filter": "unread",
+		"fields":      "id",
+		"limit":       "1000",
+	}
+	path := fmt.Sprintf("members/%s/notifications", m.ID)
+	var notifications []struct {
+		ID string `json:"id"`
+	}
+	err := m.client.Get(path, args, &notifications)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to count the unread notifications of member %s", m.ID)
+	}
+	return len(notifications), nil
+}
+
 // SetClient can be used to override this Notification's internal connection to
 // the Trello API. Normally, this is set automatically after API calls.
 func (n *Notification) SetClient(newClient *Client) {
@@ -32,3 +32,19 @@ func TestNotificationSetClient(t *testing.T) {
 		t.Error("Expected non-nil Notification.client")
 	}
 }
+
+func TestMemberUnreadNotificationCount(t *testing.T) {
+	member := &Member{ID: "me"}
+	member.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	member.client.BaseURL = server.URL()
+
+	count, err := member.UnreadNotificationCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 unread notifications, got %d", count)
+	}
+}
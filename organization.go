@@ -7,6 +7,9 @@ package trello
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // Organization represents a Trello organization or team, i.e. a collection of members and boards.
@@ -21,6 +24,9 @@ type Organization struct {
 	Website     string   `json:"website"`
 	Products    []string `json:"products"`
 	PowerUps    []string `json:"powerUps"`
+
+	// Memberships are only loaded with Arguments{"memberships": "..."}.
+	Memberships []*Membership `json:"memberships,omitempty"`
 }
 
 // GetOrganization takes an organization id and Arguments and either
@@ -47,6 +53,115 @@ func (c *Client) GetBoardsInOrganization(orgID string, extraArgs ...Arguments) (
 	return
 }
 
+// CreateBoard creates a board with the given name within the receiver
+// Organization. Any other board attribute (e.g. desc, defaultLists,
+// prefs_permissionLevel) can be passed as extra argument. The returned Board
+// carries the Organization's client and ID.
+func (o *Organization) CreateBoard(name string, extraArgs ...Arguments) (board *Board, err error) {
+	args := Arguments{
+		"name":           name,
+		"idOrganization": o.ID,
+	}
+	args.flatten(extraArgs)
+
+	err = o.client.Post("boards", args, &board)
+	if err != nil {
+		return nil, err
+	}
+	board.SetClient(o.client)
+	if board.IDOrganization == "" {
+		board.IDOrganization = o.ID
+	}
+	return
+}
+
+// AddMember invites the given email address to the receiver Organization with
+// memberType "admin" or "normal". Trello requires a fullName when the email
+// doesn't belong to an existing account, pass it as extra argument.
+//
+// The returned Membership is the one of the member with the given email, as
+// returned by Trello: pending until the invitee accepts the invitation
+// (Unconfirmed is true), or the existing one if the email already belongs to
+// a member of the Organization. Memberships is replaced with the memberships
+// returned by Trello.
+func (o *Organization) AddMember(email, memberType string, extraArgs ...Arguments) (*Membership, error) {
+	args := Arguments{
+		"email":         email,
+		"type":          memberType,
+		"members":       "all",
+		"member_fields": "email,fullName,username",
+	}
+	args.flatten(extraArgs)
+
+	path := fmt.Sprintf("organizations/%s/members", o.ID)
+	var updated struct {
+		Members     []*Member     `json:"members"`
+		Memberships []*Membership `json:"memberships"`
+	}
+	err := o.client.Put(path, args, &updated)
+	if err != nil {
+		return nil, err
+	}
+	o.Memberships = updated.Memberships
+
+	for _, member := range updated.Members {
+		if !strings.EqualFold(member.Email, email) {
+			continue
+		}
+		for _, membership := range updated.Memberships {
+			if membership.MemberID == member.ID {
+				return membership, nil
+			}
+		}
+	}
+	return nil, errors.Errorf("no membership for '%s' in organization %s", email, o.ID)
+}
+
+// RemoveMember removes the member with the given id from the receiver
+// Organization and drops its membership from Memberships.
+func (o *Organization) RemoveMember(memberID string, extraArgs ...Arguments) error {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("organizations/%s/members/%s", o.ID, memberID)
+	err := o.client.Delete(path, args, nil)
+	if err != nil {
+		return err
+	}
+
+	memberships := o.Memberships[:0]
+	for _, membership := range o.Memberships {
+		if membership.MemberID != memberID {
+			memberships = append(memberships, membership)
+		}
+	}
+	o.Memberships = memberships
+	return nil
+}
+
+// GetOrganizations takes Arguments and returns the organizations the receiver
+// Member belongs to. Arguments{"memberships": "all"} loads the memberships of
+// each organization, see Organization.MemberType.
+func (m *Member) GetOrganizations(extraArgs ...Arguments) (organizations []*Organization, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("members/%s/organizations", m.ID)
+	err = m.client.Get(path, args, &organizations)
+	for _, organization := range organizations {
+		organization.SetClient(m.client)
+	}
+	return
+}
+
+// MemberType returns the type of membership ("admin" or "normal") the member
+// with the given id has in the organization, or an empty string if the member
+// isn't found in its loaded Memberships.
+func (o *Organization) MemberType(memberID string) string {
+	for _, membership := range o.Memberships {
+		if membership.MemberID == memberID {
+			return membership.Type
+		}
+	}
+	return ""
+}
+
 // SetClient can be used to override this Organization's internal connection
 // to the Trello API. Normally, this is set automatically after API calls.
 func (o *Organization) SetClient(newClient *Client) {
@@ -6,6 +6,8 @@
 package trello
 
 import (
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -43,6 +45,206 @@ func TestOrganizationSetClient(t *testing.T) {
 	}
 }
 
+func TestMemberGetOrganizations(t *testing.T) {
+	member := &Member{ID: "me"}
+	member.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	member.client.BaseURL = server.URL()
+
+	organizations, err := member.GetOrganizations(Arguments{"memberships": "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(organizations) != 2 {
+		t.Fatalf("Expected 2 organizations, got %d", len(organizations))
+	}
+	if organizations[1].DisplayName != "Open Source Friends" {
+		t.Errorf("Expected 'Open Source Friends', got '%s'", organizations[1].DisplayName)
+	}
+	for _, organization := range organizations {
+		if organization.client == nil {
+			t.Errorf("Expected organization %s to have a client", organization.ID)
+		}
+	}
+	if role := organizations[0].MemberType("4ee7df1be582acdec80000ae"); role != "admin" {
+		t.Errorf("Expected the member to be an admin of the first organization, got '%s'", role)
+	}
+	if role := organizations[1].MemberType("4ee7df1be582acdec80000ae"); role != "normal" {
+		t.Errorf("Expected the member to be a normal member of the second organization, got '%s'", role)
+	}
+	if role := organizations[1].MemberType("4f07450bfc2105680706d822"); role != "" {
+		t.Errorf("Expected no role for an unknown member, got '%s'", role)
+	}
+}
+
+func TestOrganizationCreateBoard(t *testing.T) {
+	organization := &Organization{ID: "571ab6ad9dc91c597d6e9f90"}
+	organization.SetClient(testClient())
+	server := NewMockResponder(t, "organizations", "571ab6ad9dc91c597d6e9f90", "board-created.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/boards" {
+			t.Errorf("Expected POST /boards, got %s %s", r.Method, r.URL.Path)
+		}
+		if v := r.URL.Query().Get("idOrganization"); v != "571ab6ad9dc91c597d6e9f90" {
+			t.Errorf("Expected idOrganization '571ab6ad9dc91c597d6e9f90', got '%s'", v)
+		}
+		if v := r.URL.Query().Get("name"); v != "Roadmap" {
+			t.Errorf("Expected name 'Roadmap', got '%s'", v)
+		}
+		if v := r.URL.Query().Get("defaultLists"); v != "false" {
+			t.Errorf("Expected defaultLists 'false', got '%s'", v)
+		}
+	})
+	organization.client.BaseURL = server.URL()
+
+	board, err := organization.CreateBoard("Roadmap", Arguments{"defaultLists": "false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.ID != "5f3a1c0a7b6e2d3c4b5a0c10" {
+		t.Errorf("Expected board to pick up an ID, got '%s'", board.ID)
+	}
+	if board.IDOrganization != organization.ID {
+		t.Errorf("Expected board in organization %s, got '%s'", organization.ID, board.IDOrganization)
+	}
+	if board.client != organization.client {
+		t.Error("Expected board to carry the organization's client")
+	}
+}
+
+func TestOrganizationAddMember(t *testing.T) {
+	organization := &Organization{ID: "571ab6ad9dc91c597d6e9f90"}
+	organization.SetClient(testClient())
+	server := NewMockResponder(t, "organizations", "571ab6ad9dc91c597d6e9f90", "member-invited.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/organizations/571ab6ad9dc91c597d6e9f90/members" {
+			t.Errorf("Expected PUT on the organization members, got %s %s", r.Method, r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("email") != "jane@example.com" || q.Get("type") != "normal" || q.Get("fullName") != "Jane Smith" {
+			t.Errorf("Unexpected invitation arguments: %v", q)
+		}
+		if q.Get("members") != "all" || !strings.Contains(q.Get("member_fields"), "email") {
+			t.Errorf("Expected the members to be requested with their email, got %v", q)
+		}
+	})
+	organization.client.BaseURL = server.URL()
+
+	membership, err := organization.AddMember("jane@example.com", "normal", Arguments{"fullName": "Jane Smith"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if membership.ID != "5f3a1c0a7b6e2d3c4b5a0c21" {
+		t.Errorf("Expected the new membership, got '%s'", membership.ID)
+	}
+	if !membership.Unconfirmed {
+		t.Error("Expected the invitation to be pending")
+	}
+	if len(organization.Memberships) != 3 {
+		t.Errorf("Expected 3 memberships, got %d", len(organization.Memberships))
+	}
+}
+
+func TestOrganizationAddMemberWithLoadedMemberships(t *testing.T) {
+	organization := &Organization{
+		ID: "571ab6ad9dc91c597d6e9f90",
+		Memberships: []*Membership{
+			{ID: "571ab6ad9dc91c597d6e9f91", MemberID: "4ee7df1be582acdec80000ae", Type: "admin"},
+			{ID: "571ab6ad9dc91c597d6e9f92", MemberID: "4ee7deffe582acdec80000ac", Type: "normal"},
+		},
+	}
+	organization.SetClient(testClient())
+	server := mockResponse("organizations", "571ab6ad9dc91c597d6e9f90", "member-invited.json")
+	defer server.Close()
+	organization.client.BaseURL = server.URL
+
+	membership, err := organization.AddMember("jane@example.com", "normal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if membership.MemberID != "5f3a1c0a7b6e2d3c4b5a0c20" {
+		t.Errorf("Expected the invited member, got '%s'", membership.MemberID)
+	}
+}
+
+func TestOrganizationAddMemberAlreadyMember(t *testing.T) {
+	organization := &Organization{ID: "571ab6ad9dc91c597d6e9f90"}
+	organization.SetClient(testClient())
+	server := mockResponse("organizations", "571ab6ad9dc91c597d6e9f90", "member-invited.json")
+	defer server.Close()
+	organization.client.BaseURL = server.URL
+
+	// Another invitation is pending, but John is a member already
+	membership, err := organization.AddMember("John@Example.com", "normal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if membership.ID != "571ab6ad9dc91c597d6e9f92" || membership.Unconfirmed {
+		t.Errorf("Expected the existing membership of John, got '%s'", membership.ID)
+	}
+}
+
+func TestOrganizationAddMemberNotFound(t *testing.T) {
+	organization := &Organization{ID: "571ab6ad9dc91c597d6e9f90"}
+	organization.SetClient(testClient())
+	server := mockResponse("organizations", "571ab6ad9dc91c597d6e9f90", "member-invited.json")
+	defer server.Close()
+	organization.client.BaseURL = server.URL
+
+	if _, err := organization.AddMember("nobody@example.com", "normal"); err == nil {
+		t.Error("Expected an error without a member for the email")
+	}
+}
+
+func TestOrganizationRemoveMember(t *testing.T) {
+	organization := &Organization{
+		ID: "571ab6ad9dc91c597d6e9f90",
+		Memberships: []*Membership{
+			{ID: "571ab6ad9dc91c597d6e9f91", MemberID: "4ee7df1be582acdec80000ae", Type: "admin"},
+			{ID: "571ab6ad9dc91c597d6e9f92", MemberID: "4ee7deffe582acdec80000ac", Type: "normal"},
+		},
+	}
+	organization.SetClient(testClient())
+	server := NewMockResponder(t, "organizations", "571ab6ad9dc91c597d6e9f90", "member-removed.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/organizations/571ab6ad9dc91c597d6e9f90/members/4ee7deffe582acdec80000ac" {
+			t.Errorf("Expected DELETE on the organization member, got %s %s", r.Method, r.URL.Path)
+		}
+	})
+	organization.client.BaseURL = server.URL()
+
+	err := organization.RemoveMember("4ee7deffe582acdec80000ac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(organization.Memberships) != 1 || organization.MemberType("4ee7deffe582acdec80000ac") != "" {
+		t.Errorf("Expected the membership to be dropped, got %d memberships", len(organization.Memberships))
+	}
+}
+
+func TestOrganizationRemoveMemberError(t *testing.T) {
+	organization := &Organization{
+		ID:          "571ab6ad9dc91c597d6e9f90",
+		Memberships: []*Membership{{ID: "571ab6ad9dc91c597d6e9f91", MemberID: "4ee7df1be582acdec80000ae"}},
+	}
+	organization.SetClient(testClient())
+	server := mockErrorResponse(http.StatusUnauthorized)
+	defer server.Close()
+	organization.client.BaseURL = server.URL
+
+	err := organization.RemoveMember("4ee7df1be582acdec80000ae")
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission denied error, got %v", err)
+	}
+	if len(organization.Memberships) != 1 {
+		t.Error("Expected memberships to be kept on error")
+	}
+}
+
 func testOrganization(t *testing.T) *Organization {
 	client := testClient()
 	client.BaseURL = mockResponse("organizations", "culturefoundry.json").URL
@@ -52,3 +254,29 @@ func testOrganization(t *testing.T) *Organization {
 	}
 	return organization
 }
+
+func TestGetOrganizationMemberships(t *testing.T) {
+	organization := testOrganization(t)
+
+	server := NewMockResponder(t)
+	defer server.Close()
+	organization.client.BaseURL = server.URL()
+
+	memberships, err := organization.GetMemberships()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(memberships) != 3 {
+		t.Fatalf("Expected 3 memberships, got %d", len(memberships))
+	}
+	if memberships[0].Type != "admin" {
+		t.Errorf("Expected the first member to be an admin, got '%s'", memberships[0].Type)
+	}
+	if memberships[1].Type != "normal" || memberships[1].Deactivated {
+		t.Errorf("Expected an active normal member, got '%s' (deactivated: %t)", memberships[1].Type, memberships[1].Deactivated)
+	}
+	if !memberships[2].Deactivated {
+		t.Error("Expected the last member to be deactivated")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PluginData represents the data a Power-Up stored on a card or board.
+// Value is kept exactly as returned by Trello (usually a JSON encoded string)
+// so callers can decode it with their own plugin's schema.
+// https://developers.trello.com/reference/#pluginData
+type PluginData struct {
+	ID       string          `json:"id"`
+	IDPlugin string          `json:"idPlugin"`
+	Scope    string          `json:"scope"`
+	IDModel  string          `json:"idModel"`
+	Value    json.RawMessage `json:"value"`
+	Access   string          `json:"access"`
+}
+
+// GetPluginData takes Arguments and returns the Power-Up data stored on the receiver Card.
+func (c *Card) GetPluginData(extraArgs ...Arguments) (pluginData []*PluginData, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("cards/%s/pluginData", c.ID)
+	err = c.client.Get(path, args, &pluginData)
+	return
+}
+
+// GetPluginData takes Arguments and returns the Power-Up data stored on the receiver Board.
+func (b *Board) GetPluginData(extraArgs ...Arguments) (pluginData []*PluginData, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("boards/%s/pluginData", b.ID)
+	err = b.client.Get(path, args, &pluginData)
+	return
+}
@@ -0,0 +1,71 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGetPluginDataOnCard(t *testing.T) {
+	card := testCard(t)
+	card.client.BaseURL = mockResponse("pluginData", "card-plugin-data.json").URL
+	pluginData, err := card.GetPluginData(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pluginData) != 1 {
+		t.Fatalf("Expected 1 plugin data entry, got %d", len(pluginData))
+	}
+	expected := `"{\"estimate\": 3,  \"notes\":\"keep  this   spacing\"}"`
+	if string(pluginData[0].Value) != expected {
+		t.Errorf("Expected raw value %s, got %s", expected, pluginData[0].Value)
+	}
+	if pluginData[0].Scope != "card" {
+		t.Errorf("Expected scope 'card', got '%s'", pluginData[0].Scope)
+	}
+}
+
+func TestGetPluginDataOnBoard(t *testing.T) {
+	board := testBoard(t)
+	board.client.BaseURL = mockResponse("pluginData", "board-plugin-data.json").URL
+	pluginData, err := board.GetPluginData(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pluginData) != 2 {
+		t.Fatalf("Expected 2 plugin data entries, got %d", len(pluginData))
+	}
+	if pluginData[1].Access != "private" {
+		t.Errorf("Expected access 'private', got '%s'", pluginData[1].Access)
+	}
+
+	// The raw value must survive a round trip unchanged
+	original := string(pluginData[0].Value)
+	b, err := json.Marshal(pluginData[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTripped := PluginData{}
+	err = json.Unmarshal(b, &roundTripped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(roundTripped.Value) != original {
+		t.Errorf("Expected value %s after round trip, got %s", original, roundTripped.Value)
+	}
+
+	var decoded string
+	err = json.Unmarshal(roundTripped.Value, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != "{\"sprint\":\"2020-31\",\n \"velocity\": 21}" {
+		t.Errorf("Unexpected decoded plugin value %q", decoded)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Reaction represents an emoji reaction of a member to a comment Action.
+// https://developers.trello.com/reference/#actionsidactionreactions
+type Reaction struct {
+	client   *Client
+	ID       string `json:"id"`
+	IDMember string `json:"idMember"`
+	IDModel  string `json:"idModel"`
+	IDEmoji  string `json:"idEmoji"`
+	Emoji    struct {
+		Unified       string `json:"unified"`
+		Native        string `json:"native"`
+		Name          string `json:"name"`
+		SkinVariation string `json:"skinVariation"`
+		ShortName     string `json:"shortName"`
+	} `json:"emoji"`
+}
+
+// GetReactions takes Arguments and returns the reactions to the receiver
+// comment Action.
+func (a *Action) GetReactions(extraArgs ...Arguments) (reactions []*Reaction, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("actions/%s/reactions", a.ID)
+	err = a.client.Get(path, args, &reactions)
+	for i := range reactions {
+		reactions[i].SetClient(a.client)
+	}
+	return
+}
+
+// AddReaction reacts to the receiver comment Action with the emoji of the
+// given short name, e.g. "thumbsup" or ":+1:". Surrounding colons are
+// stripped, Trello expects the bare short name.
+func (a *Action) AddReaction(shortName string) (*Reaction, error) {
+	shortName = strings.Trim(shortName, ":")
+	if shortName == "" {
+		return nil, errors.Errorf("Can't add a reaction without emoji to action %s", a.ID)
+	}
+
+	path := fmt.Sprintf("actions/%s/reactions", a.ID)
+	source := map[string]string{"shortName": shortName}
+	reaction := &Reaction{}
+	err := a.client.PostJSON(path, Defaults(), source, reaction)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error adding reaction '%s' to action %s", shortName, a.ID)
+	}
+	reaction.SetClient(a.client)
+	return reaction, nil
+}
+
+// ReactionSummary takes Arguments and returns how many times the receiver
+// comment Action was reacted to with each emoji, keyed by short name, e.g.
+// {"+1": 3, "tada": 2}. Skin tone variations count towards their base emoji.
+// Only comments can be reacted to, for any other type of Action an error is
+// returned without making a request.
+func (a *Action) ReactionSummary(extraArgs ...Arguments) (map[string]int, error) {
+	if !a.DidCommentCard() {
+		return nil, errors.Errorf("Action %s is a %s action, only commentCard actions have reactions", a.ID, a.Type)
+	}
+
+	reactions, err := a.GetReactions(extraArgs...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get reactions of action %s", a.ID)
+	}
+
+	summary := make(map[string]int)
+	for _, reaction := range reactions {
+		summary[reaction.Emoji.ShortName]++
+	}
+	return summary, nil
+}
+
+// SetClient can be used to override this Reaction's internal connection to
+// the Trello API. Normally, this is set automatically after API calls.
+func (r *Reaction) SetClient(newClient *Client) {
+	r.client = newClient
+}
@@ -0,0 +1,110 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestActionGetReactions(t *testing.T) {
+	action := &Action{ID: "5fd20c0a7b6e2d3c4b5a0001"}
+	action.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	action.client.BaseURL = server.URL()
+
+	reactions, err := action.GetReactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reactions) != 2 {
+		t.Fatalf("Expected 2 reactions, got %d", len(reactions))
+	}
+	if reactions[0].IDMember != "4ee7df1be582acdec80000ae" || reactions[0].Emoji.ShortName != "+1" {
+		t.Errorf("Unexpected first reaction %+v", reactions[0])
+	}
+	if reactions[1].IDEmoji != "1F389" || reactions[1].Emoji.Native != "🎉" {
+		t.Errorf("Unexpected second reaction %+v", reactions[1])
+	}
+	if reactions[0].client == nil {
+		t.Error("Expected reaction to pick up a client")
+	}
+}
+
+func TestActionAddReaction(t *testing.T) {
+	action := &Action{ID: "5fd20c0a7b6e2d3c4b5a0001"}
+	action.SetClient(testClient())
+	server := NewMockResponder(t, "actions", "reaction-create.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/actions/5fd20c0a7b6e2d3c4b5a0001/reactions" {
+			t.Errorf("Expected POST on the action reactions, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"shortName":"rocket"}` {
+			t.Errorf("Unexpected body %s", body)
+		}
+	})
+	action.client.BaseURL = server.URL()
+
+	reaction, err := action.AddReaction(":rocket:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reaction.ID != "5fd30c0a7b6e2d3c4b5a0103" || reaction.Emoji.ShortName != "rocket" {
+		t.Errorf("Unexpected reaction %+v", reaction)
+	}
+	if reaction.client == nil {
+		t.Error("Expected reaction to pick up a client")
+	}
+
+	_, err = action.AddReaction("::")
+	if err == nil {
+		t.Error("Expected an error for an empty short name")
+	}
+}
+
+func TestActionReactionSummary(t *testing.T) {
+	action := &Action{ID: "5fd20c0a7b6e2d3c4b5a0002", Type: "commentCard"}
+	action.SetClient(testClient())
+	server := NewMockResponder(t)
+	defer server.Close()
+	action.client.BaseURL = server.URL()
+
+	summary, err := action.ReactionSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{"+1": 3, "tada": 2}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("Expected %v, got %v", expected, summary)
+	}
+}
+
+func TestActionReactionSummaryNotAComment(t *testing.T) {
+	action := &Action{ID: "5fd20c0a7b6e2d3c4b5a0003", Type: "updateCard"}
+	action.SetClient(testClient())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		rw.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	action.client.BaseURL = server.URL
+
+	_, err := action.ReactionSummary()
+	if err == nil || !strings.Contains(err.Error(), "only commentCard actions") {
+		t.Errorf("Expected an error about the action type, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Didn't expect a request, got %d", requests)
+	}
+}
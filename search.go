@@ -5,22 +5,64 @@
 
 package trello
 
+import (
+	"strconv"
+	"strings"
+)
+
 // SearchResult represents a search result as collections of various
 // types returned by a search, e.g. Cards or Boards.
 type SearchResult struct {
-	Options SearchOptions `json:"options"`
-	Actions []*Action     `json:"actions,omitempty"`
-	Cards   []*Card       `json:"cards,omitempty"`
-	Boards  []*Board      `json:"boards,omitempty"`
-	Members []*Member     `json:"members,omitempty"`
+	Options       SearchOptions   `json:"options"`
+	Actions       []*Action       `json:"actions,omitempty"`
+	Cards         []*Card         `json:"cards,omitempty"`
+	Boards        []*Board        `json:"boards,omitempty"`
+	Members       []*Member       `json:"members,omitempty"`
+	Organizations []*Organization `json:"organizations,omitempty"`
 }
 
-// SearchOptions contains options for search requests.
+// SearchOptions contains options for search requests. Terms and Modifiers
+// are only ever filled in by Trello when it echoes back how a query was
+// parsed; the remaining fields scope the request made by Client.Search.
 type SearchOptions struct {
 	Terms      []SearchTerm     `json:"terms"`
 	Modifiers  []SearchModifier `json:"modifiers,omitempty"`
 	ModelTypes []string         `json:"modelTypes,omitempty"`
 	Partial    bool             `json:"partial"`
+
+	BoardsLimit     int      `json:"-"`
+	CardsLimit      int      `json:"-"`
+	CardsPage       int      `json:"-"`
+	IDBoards        []string `json:"-"`
+	IDOrganizations []string `json:"-"`
+}
+
+// arguments translates the options into the query parameters understood by
+// the search endpoint. Zero values are left out so Trello's defaults apply.
+func (o SearchOptions) arguments() Arguments {
+	args := Arguments{}
+	if len(o.ModelTypes) > 0 {
+		args["modelTypes"] = strings.Join(o.ModelTypes, ",")
+	}
+	if o.BoardsLimit > 0 {
+		args["boards_limit"] = strconv.Itoa(o.BoardsLimit)
+	}
+	if o.CardsLimit > 0 {
+		args["cards_limit"] = strconv.Itoa(o.CardsLimit)
+	}
+	if o.CardsPage > 0 {
+		args["cards_page"] = strconv.Itoa(o.CardsPage)
+	}
+	if len(o.IDBoards) > 0 {
+		args["idBoards"] = strings.Join(o.IDBoards, ",")
+	}
+	if len(o.IDOrganizations) > 0 {
+		args["idOrganizations"] = strings.Join(o.IDOrganizations, ",")
+	}
+	if o.Partial {
+		args["partial"] = "true"
+	}
+	return args
 }
 
 // SearchModifier is wrapper for a search string.
@@ -34,6 +76,35 @@ type SearchTerm struct {
 	Negated bool   `json:"negated,omitempty"`
 }
 
+// Search runs query against every model type selected in opts (all of them
+// when opts.ModelTypes is empty) and returns the combined result. Set
+// opts.Partial for prefix matching, e.g. when backing a typeahead.
+func (c *Client) Search(query string, opts SearchOptions, extraArgs ...Arguments) (*SearchResult, error) {
+	args := opts.arguments()
+	args["query"] = query
+	args.flatten(extraArgs)
+	res := &SearchResult{}
+	if err := c.Get("search", args, res); err != nil {
+		return nil, err
+	}
+	for _, action := range res.Actions {
+		action.SetClient(c)
+	}
+	for _, card := range res.Cards {
+		card.SetClient(c)
+	}
+	for _, board := range res.Boards {
+		board.SetClient(c)
+	}
+	for _, member := range res.Members {
+		member.SetClient(c)
+	}
+	for _, organization := range res.Organizations {
+		organization.SetClient(c)
+	}
+	return res, nil
+}
+
 // SearchCards takes a query string and Arguments and returns a slice of Cards or an error.
 func (c *Client) SearchCards(query string, extraArgs ...Arguments) (cards []*Card, err error) {
 	args := Arguments{
@@ -6,6 +6,7 @@
 package trello
 
 import (
+	"net/http"
 	"testing"
 )
 
@@ -47,3 +48,79 @@ func TestSearchMembers(t *testing.T) {
 		t.Errorf("Expected 3 member search result entries. Got %d.", len(members))
 	}
 }
+
+func TestSearch(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "search", "multi-model-response.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Path != "/search" {
+			t.Errorf("Expected a request to /search, got %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		expected := map[string]string{
+			"query":           "roadm",
+			"modelTypes":      "cards,boards,members,organizations",
+			"boards_limit":    "5",
+			"cards_limit":     "20",
+			"cards_page":      "1",
+			"idBoards":        "5f60c0a7b6e2d3c4b5a0e101,mine",
+			"idOrganizations": "5f60c0a7b6e2d3c4b5a0e301",
+			"partial":         "true",
+		}
+		for key, value := range expected {
+			if got := query.Get(key); got != value {
+				t.Errorf("Expected %s=%s, got '%s'", key, value, got)
+			}
+		}
+	})
+	c.BaseURL = server.URL()
+
+	res, err := c.Search("roadm", SearchOptions{
+		ModelTypes:      []string{"cards", "boards", "members", "organizations"},
+		BoardsLimit:     5,
+		CardsLimit:      20,
+		CardsPage:       1,
+		Partial:         true,
+		IDBoards:        []string{"5f60c0a7b6e2d3c4b5a0e101", "mine"},
+		IDOrganizations: []string{"5f60c0a7b6e2d3c4b5a0e301"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Cards) != 2 || len(res.Boards) != 1 || len(res.Members) != 1 || len(res.Organizations) != 1 {
+		t.Fatalf("Expected 2 cards, 1 board, 1 member and 1 organization, got %d, %d, %d and %d",
+			len(res.Cards), len(res.Boards), len(res.Members), len(res.Organizations))
+	}
+	if res.Cards[0].client == nil || res.Boards[0].client == nil || res.Members[0].client == nil || res.Organizations[0].client == nil {
+		t.Error("Expected every result to have its client set")
+	}
+	if res.Organizations[0].DisplayName != "Roadmap Team" {
+		t.Errorf("Unexpected organization '%s'", res.Organizations[0].DisplayName)
+	}
+	if !res.Options.Partial {
+		t.Error("Expected the echoed options to report a partial search")
+	}
+}
+
+func TestSearchDefaults(t *testing.T) {
+	c := testClient()
+	server := NewMockResponder(t, "search", "multi-model-response.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("query") != "roadmap" {
+			t.Errorf("Expected query=roadmap, got '%s'", query.Get("query"))
+		}
+		for _, key := range []string{"modelTypes", "boards_limit", "cards_limit", "cards_page", "idBoards", "idOrganizations", "partial"} {
+			if _, ok := query[key]; ok {
+				t.Errorf("Expected %s to be left to Trello's default, got '%s'", key, query.Get(key))
+			}
+		}
+	})
+	c.BaseURL = server.URL()
+
+	if _, err := c.Search("roadmap", SearchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
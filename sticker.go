@@ -0,0 +1,72 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Sticker represents a sticker placed on a Card. Image holds either the name
+// of one of Trello's default stickers (e.g. "thumbsup") or the id of a custom
+// sticker.
+// https://developers.trello.com/reference/#cardsidstickers
+type Sticker struct {
+	client   *Client
+	ID       string  `json:"id"`
+	Image    string  `json:"image"`
+	ImageURL string  `json:"imageUrl"`
+	Top      float64 `json:"top"`
+	Left     float64 `json:"left"`
+	ZIndex   int     `json:"zIndex"`
+	Rotate   float64 `json:"rotate"`
+}
+
+// GetStickers takes Arguments and returns the stickers of the receiver Card.
+func (c *Card) GetStickers(extraArgs ...Arguments) (stickers []*Sticker, err error) {
+	args := flattenArguments(extraArgs)
+	path := fmt.Sprintf("cards/%s/stickers", c.ID)
+	err = c.client.Get(path, args, &stickers)
+	for i := range stickers {
+		stickers[i].SetClient(c.client)
+	}
+	return
+}
+
+// AddSticker takes a Sticker and places it on the card. The sticker picks up
+// its ID and the remaining attributes from the response.
+func (c *Card) AddSticker(sticker *Sticker, extraArgs ...Arguments) error {
+	path := fmt.Sprintf("cards/%s/stickers", c.ID)
+	args := Arguments{
+		"image":  sticker.Image,
+		"top":    strconv.FormatFloat(sticker.Top, 'g', -1, 64),
+		"left":   strconv.FormatFloat(sticker.Left, 'g', -1, 64),
+		"zIndex": strconv.Itoa(sticker.ZIndex),
+		"rotate": strconv.FormatFloat(sticker.Rotate, 'g', -1, 64),
+	}
+	args.flatten(extraArgs)
+	err := c.client.Post(path, args, sticker)
+	if err == nil {
+		sticker.SetClient(c.client)
+	} else {
+		err = errors.Wrapf(err, "Error adding sticker to card %s", c.ID)
+	}
+	return err
+}
+
+// RemoveSticker takes a sticker id and removes the sticker from the card.
+func (c *Card) RemoveSticker(stickerID string) error {
+	path := fmt.Sprintf("cards/%s/stickers/%s", c.ID, stickerID)
+	return c.client.Delete(path, Defaults(), nil)
+}
+
+// SetClient can be used to override this Sticker's internal connection to the
+// Trello API. Normally, this is set automatically after API calls.
+func (s *Sticker) SetClient(newClient *Client) {
+	s.client = newClient
+}
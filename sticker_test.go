@@ -0,0 +1,89 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetStickersOnCard(t *testing.T) {
+	card := testCard(t)
+	card.client.BaseURL = mockResponse("stickers", "card-stickers.json").URL
+	stickers, err := card.GetStickers(Defaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stickers) != 2 {
+		t.Fatalf("Expected 2 stickers, got %d", len(stickers))
+	}
+	if stickers[0].Image != "thumbsup" {
+		t.Errorf("Expected default sticker 'thumbsup', got '%s'", stickers[0].Image)
+	}
+	if stickers[1].Image != "5f20a0b1c2d3e4f5a6b7c8d9" {
+		t.Errorf("Expected custom sticker id, got '%s'", stickers[1].Image)
+	}
+	if stickers[1].Rotate != -10 || stickers[1].ZIndex != 2 {
+		t.Errorf("Unexpected sticker placement %+v", stickers[1])
+	}
+	if stickers[0].client == nil {
+		t.Error("Expected sticker to pick up a client")
+	}
+}
+
+func TestAddStickerToCard(t *testing.T) {
+	card := testCard(t)
+	server := NewMockResponder(t, "stickers", "sticker-create.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected a POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/cards/4eea503d91e31d174600008f/stickers" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("image") != "thumbsup" {
+			t.Errorf("Expected image 'thumbsup', got '%s'", r.URL.Query().Get("image"))
+		}
+		if r.URL.Query().Get("left") != "30" {
+			t.Errorf("Expected left '30', got '%s'", r.URL.Query().Get("left"))
+		}
+	})
+	defer server.Close()
+	card.client.BaseURL = server.URL()
+
+	sticker := Sticker{Image: "thumbsup", Top: 5, Left: 30, ZIndex: 3}
+	err := card.AddSticker(&sticker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sticker.ID != "5f20b1c2d3e4f5a6b7c8d9e2" {
+		t.Errorf("Expected sticker to pick up an ID, got '%s'", sticker.ID)
+	}
+	if sticker.ImageURL == "" {
+		t.Error("Expected sticker to pick up an image URL")
+	}
+}
+
+func TestRemoveStickerFromCard(t *testing.T) {
+	card := testCard(t)
+	server := NewMockResponder(t, "stickers", "sticker-create.json")
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected a DELETE, got %s", r.Method)
+		}
+		if r.URL.Path != "/cards/4eea503d91e31d174600008f/stickers/5f20b1c2d3e4f5a6b7c8d9e2" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	})
+	defer server.Close()
+	card.client.BaseURL = server.URL()
+
+	err := card.RemoveSticker("5f20b1c2d3e4f5a6b7c8d9e2")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -7,7 +7,10 @@ package trello
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // Token represents Trello tokens. Tokens can be used for setting up Webhooks among other things.
@@ -41,8 +44,57 @@ func (c *Client) GetToken(tokenID string, extraArgs ...Arguments) (token *Token,
 	return
 }
 
+// GetMyToken takes Arguments and GETs the Token the receiver Client
+// authenticates with, e.g. to check its permissions and expiration before
+// making further calls.
+func (c *Client) GetMyToken(extraArgs ...Arguments) (*Token, error) {
+	return c.GetToken(c.Token, extraArgs...)
+}
+
+// IsExpired returns true if the token has an expiration date which has
+// passed. Tokens created to never expire have no DateExpires and never
+// expire.
+func (t *Token) IsExpired() bool {
+	return t.DateExpires != nil && !t.DateExpires.After(time.Now())
+}
+
 // SetClient can be used to override this Token's internal connection to the
 // Trello API. Normally, this is set automatically after API calls.
 func (t *Token) SetClient(newClient *Client) {
 	t.client = newClient
 }
+
+// authorizeURL is the page on which a Trello member grants an application a token.
+const authorizeURL = "https://trello.com/1/authorize"
+
+// AuthorizeURL returns the URL to send a Trello member to in order to grant
+// the application named appName a token for the receiver Client's key. The
+// scope is made of "read", "write" and "account", expiration is one of
+// "1hour", "1day", "30days" or "never". No request is made. Further
+// arguments, e.g. Arguments{"return_url": ..., "callback_method": "fragment"},
+// are added to the URL.
+func (c *Client) AuthorizeURL(appName string, scope []string, expiration string, extraArgs ...Arguments) (string, error) {
+	if len(scope) == 0 {
+		return "", errors.New("AuthorizeURL() requires at least one scope")
+	}
+	for _, s := range scope {
+		if s != "read" && s != "write" && s != "account" {
+			return "", errors.Errorf("Unsupported scope '%s'", s)
+		}
+	}
+	switch expiration {
+	case "1hour", "1day", "30days", "never":
+	default:
+		return "", errors.Errorf("Unsupported expiration '%s'", expiration)
+	}
+
+	args := Arguments{
+		"key":           c.Key,
+		"name":          appName,
+		"scope":         strings.Join(scope, ","),
+		"expiration":    expiration,
+		"response_type": "token",
+	}
+	args.flatten(extraArgs)
+	return fmt.Sprintf("%s?%s", authorizeURL, args.ToURLValues().Encode()), nil
+}
@@ -6,6 +6,7 @@
 package trello
 
 import (
+	"net/http"
 	"testing"
 	"time"
 )
@@ -67,3 +68,91 @@ func testToken(t *testing.T) *Token {
 	}
 	return token
 }
+
+func TestGetMyToken(t *testing.T) {
+	client := testClient()
+	server := NewMockResponder(t, "tokens", "token.json")
+	defer server.Close()
+	server.AssertRequest(func(t *testing.T, r *http.Request) {
+		if r.URL.Path != "/tokens/pass" {
+			t.Errorf("Expected the client's token to be requested, got %s", r.URL.Path)
+		}
+	})
+	client.BaseURL = server.URL()
+
+	token, err := client.GetMyToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.DateExpires != nil {
+		t.Errorf("Expected a never-expiring token, got expiration %v", token.DateExpires)
+	}
+	if token.IsExpired() {
+		t.Error("Expected a never-expiring token not to be expired")
+	}
+}
+
+func TestTokenIsExpired(t *testing.T) {
+	client := testClient()
+	client.BaseURL = mockResponse("tokens", "token-expiring.json").URL
+	token, err := client.GetToken("tOkenId")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !token.IsExpired() {
+		t.Errorf("Expected token expiring %v to be expired", token.DateExpires)
+	}
+
+	future := time.Now().Add(time.Hour)
+	token.DateExpires = &future
+	if token.IsExpired() {
+		t.Error("Expected a token expiring in an hour not to be expired")
+	}
+}
+
+func TestAuthorizeURL(t *testing.T) {
+	c := NewClient("abc123", "")
+
+	tests := []struct {
+		scope      []string
+		expiration string
+		extraArgs  []Arguments
+		expected   string
+	}{
+		{
+			[]string{"read"},
+			"1day",
+			nil,
+			"https://trello.com/1/authorize?expiration=1day&key=abc123&name=Card+Sync+%26+Co&response_type=token&scope=read",
+		},
+		{
+			[]string{"read", "write", "account"},
+			"never",
+			[]Arguments{{"return_url": "https://example.com/callback?x=1", "callback_method": "fragment"}},
+			"https://trello.com/1/authorize?callback_method=fragment&expiration=never&key=abc123&name=Card+Sync+%26+Co&response_type=token&return_url=https%3A%2F%2Fexample.com%2Fcallback%3Fx%3D1&scope=read%2Cwrite%2Caccount",
+		},
+	}
+	for _, test := range tests {
+		url, err := c.AuthorizeURL("Card Sync & Co", test.scope, test.expiration, test.extraArgs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url != test.expected {
+			t.Errorf("Expected URL\n%s\ngot\n%s", test.expected, url)
+		}
+	}
+}
+
+func TestAuthorizeURLInvalid(t *testing.T) {
+	c := NewClient("abc123", "")
+
+	if _, err := c.AuthorizeURL("App", []string{"read", "admin"}, "1day"); err == nil {
+		t.Error("Expected an error for an unsupported scope")
+	}
+	if _, err := c.AuthorizeURL("App", nil, "1day"); err == nil {
+		t.Error("Expected an error for an empty scope")
+	}
+	if _, err := c.AuthorizeURL("App", []string{"read"}, "2days"); err == nil {
+		t.Error("Expected an error for an unsupported expiration")
+	}
+}
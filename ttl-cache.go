@@ -0,0 +1,102 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// ttlCacheInit guards the lazy creation of the caches of Boards and Clients.
+var ttlCacheInit sync.Mutex
+
+// ttlCache holds the results of recent fetches keyed by string, each for the
+// TTL it was fetched with. Concurrent misses of the same key share a single
+// fetch. Expired entries are dropped on the next access to the cache. The
+// zero value is an empty cache ready to use.
+type ttlCache struct {
+	mu      sync.Mutex
+	entries map[string]*ttlCacheEntry
+}
+
+type ttlCacheEntry struct {
+	done      chan struct{} // closed once the fetch returned
+	fetching  bool
+	value     interface{}
+	err       error
+	fetchedAt time.Time
+	ttl       time.Duration
+}
+
+// lazyTTLCache returns the cache *cache points to, creating it first if it
+// doesn't exist yet.
+func lazyTTLCache(cache **ttlCache) *ttlCache {
+	ttlCacheInit.Lock()
+	defer ttlCacheInit.Unlock()
+	if *cache == nil {
+		*cache = &ttlCache{}
+	}
+	return *cache
+}
+
+// get returns the value cached for key if it was fetched less than ttl ago.
+// Otherwise it calls fetch and caches the value it returns for ttl, unless
+// fetch fails. Callers missing the key while it's being fetched wait for
+// that fetch and return its result instead of fetching again.
+func (c *ttlCache) get(key string, ttl time.Duration, fetch func() (interface{}, error)) (interface{}, error) {
+	now := time.Now()
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[string]*ttlCacheEntry{}
+	}
+	for k, entry := range c.entries {
+		if !entry.fetching && now.Sub(entry.fetchedAt) >= entry.ttl {
+			delete(c.entries, k)
+		}
+	}
+
+	if entry, ok := c.entries[key]; ok {
+		if entry.fetching {
+			c.mu.Unlock()
+			<-entry.done
+			return entry.value, entry.err
+		}
+		if now.Sub(entry.fetchedAt) < ttl {
+			c.mu.Unlock()
+			return entry.value, nil
+		}
+	}
+
+	entry := &ttlCacheEntry{done: make(chan struct{}), fetching: true, ttl: ttl}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	value, err := fetch()
+
+	c.mu.Lock()
+	entry.value, entry.err = value, err
+	entry.fetching = false
+	entry.fetchedAt = time.Now()
+	if (err != nil || ttl <= 0) && c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+	return value, err
+}
+
+// invalidate drops the entries whose key starts with prefix. Fetches in
+// progress for them aren't cached once they return.
+func (c *ttlCache) invalidate(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright © 2016 Aaron Longwell
+//
+// Use of this source code is governed by an MIT license.
+// Details in the LICENSE file.
+
+package trello
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTTLCacheSharesFetch(t *testing.T) {
+	cache := &ttlCache{}
+	release := make(chan struct{})
+	var mu sync.Mutex
+	fetches := 0
+	fetch := func() (interface{}, error) {
+		mu.Lock()
+		fetches++
+		mu.Unlock()
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.get("key", time.Minute, fetch)
+			if err != nil || value != "value" {
+				t.Errorf("Expected the fetched value, got %v and %v", value, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("Expected a single fetch, got %d", fetches)
+	}
+}
+
+func TestTTLCacheEvictsExpired(t *testing.T) {
+	cache := &ttlCache{}
+	fetch := func() (interface{}, error) { return "value", nil }
+
+	cache.get("short", time.Millisecond, fetch)
+	cache.get("long", time.Minute, fetch)
+	time.Sleep(5 * time.Millisecond)
+	cache.get("long", time.Minute, fetch)
+
+	if _, ok := cache.entries["short"]; ok {
+		t.Error("Expected the expired entry to be evicted")
+	}
+	if _, ok := cache.entries["long"]; !ok {
+		t.Error("Expected the entry within its TTL to be kept")
+	}
+}
+
+func TestTTLCacheInvalidate(t *testing.T) {
+	cache := &ttlCache{}
+	fetches := 0
+	fetch := func() (interface{}, error) {
+		fetches++
+		return fetches, nil
+	}
+
+	cache.get("board1?fields=all", time.Minute, fetch)
+	cache.get("board2?fields=all", time.Minute, fetch)
+	cache.invalidate("board1?")
+
+	if value, _ := cache.get("board1?fields=all", time.Minute, fetch); value != 3 {
+		t.Errorf("Expected the invalidated entry to be fetched again, got %v", value)
+	}
+	if value, _ := cache.get("board2?fields=all", time.Minute, fetch); value != 2 {
+		t.Errorf("Expected the other entry to be kept, got %v", value)
+	}
+}